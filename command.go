@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
+)
+
+// Exit codes of the subcommands, so that scripts can tell the failures apart.
+const (
+	exitOK        = 0
+	exitError     = 1
+	exitUsage     = 2
+	exitNoEvents  = 3
+	exitAuth      = 4
+	exitThrottled = 5
+)
+
+// exitCode returns the exit code of an error fetching log events.
+func exitCode(err error) int {
+	switch {
+	case aws.IsAuthError(err):
+		return exitAuth
+	case aws.IsThrottlingError(err):
+		return exitThrottled
+	default:
+		return exitError
+	}
+}
+
+// loadConfig loads the configuration of a subcommand: defaults, config file, environment
+// and the flags applied by applyFlags. The configured time zone becomes the local time zone.
+func loadConfig(path string, applyFlags func(*config.Config) error) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err == nil {
+		err = applyFlags(cfg)
+	}
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		return nil, err
+	}
+	loc, _ := cfg.Location()
+	time.Local = loc
+	return cfg, nil
+}
+
+// runConfig runs the config subcommand and returns the exit code.
+//
+//	config validate [-config FILE]   checks the configuration file and the environment overrides
+func runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintln(os.Stderr, "usage: cloudwatch-log-tui config validate [-config FILE]")
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	configPath := fs.String("config", "", "configuration file (default: $XDG_CONFIG_HOME/cloudwatch-log-tui/"+config.ConfigFile+")")
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		return exitError
+	}
+
+	if cfg.Path() == "" {
+		path, _ := config.DefaultPath()
+		fmt.Printf("no configuration file at %s, using the defaults\n", path)
+	} else {
+		fmt.Printf("%s is valid\n", cfg.Path())
+	}
+	if err := cfg.Print(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to print the configuration: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/state"
+)
+
+// deepLink is a log event view to open at startup, given by flags or a CloudWatch console URL.
+type deepLink struct {
+	groups  streamList
+	streams streamList
+	since   string
+	filter  string
+	url     string
+}
+
+// isSet returns true if a view to open was given.
+func (d *deepLink) isSet() bool {
+	return len(d.groups) > 0 || len(d.streams) > 0 || d.since != "" || d.filter != "" || d.url != ""
+}
+
+// view returns the view to open and the region of a console URL, if any.
+// The flags override the parts of the console URL, and the time range defaults to the configured one.
+func (d *deepLink) view(cfg *config.Config) (config.Bookmark, string, error) {
+	var b config.Bookmark
+	var region string
+	if d.url != "" {
+		link, err := aws.ParseConsoleURL(d.url)
+		if err != nil {
+			return b, "", err
+		}
+		region = link.Region
+		b.LogGroupName = link.LogGroupName
+		b.LogStreams = link.LogStreamNames
+		b.FilterPattern = link.FilterPattern
+		switch {
+		case link.Relative > 0:
+			b.Relative = state.FormatRelative(link.Relative)
+		case !link.Start.IsZero():
+			b.Start, b.End = link.Start, link.End
+		}
+	}
+
+	if len(d.groups) > 0 {
+		b.LogGroupName = d.groups[0]
+		b.LogGroupNames = nil
+		if len(d.groups) > 1 {
+			b.LogGroupNames = d.groups
+		}
+	}
+	if len(d.streams) > 0 {
+		b.LogStreams = d.streams
+	}
+	if d.filter != "" {
+		b.FilterPattern = d.filter
+	}
+	if _, err := aws.ParseFilterPattern(b.FilterPattern); err != nil {
+		return b, "", fmt.Errorf("invalid -filter: %w", err)
+	}
+	if b.LogGroupName == "" {
+		return b, "", errors.New("-group is required to open log events")
+	}
+	if len(b.LogStreams) > 0 && len(b.Groups()) > 1 {
+		return b, "", errors.New("-stream can only be used with a single -group")
+	}
+
+	if d.since != "" {
+		tr, err := state.ParseTimeRange(d.since, time.Now())
+		if err != nil {
+			return b, "", fmt.Errorf("invalid -since: %w", err)
+		}
+		b.Relative = ""
+		if tr.Relative > 0 {
+			b.Relative = state.FormatRelative(tr.Relative)
+		} else {
+			b.Start, b.End = tr.Start, tr.End
+		}
+	}
+	if b.Relative == "" && b.Start.IsZero() {
+		d, err := cfg.RelativeTime()
+		if err != nil {
+			return b, "", err
+		}
+		b.Relative = state.FormatRelative(d)
+	}
+	return b, region, nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/state"
+)
+
+// runExport runs the export subcommand and returns the exit code.
+//
+//	export -group G -start T [-end T] -out FILE [-format auto|raw|jsonl|csv|text] [-gzip]
+//
+// saves the log events of a log group to a file, like the Save button of the app.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	group := fs.String("group", "", "log group to export the events of (required)")
+	var streams streamList
+	fs.Var(&streams, "stream", "log stream to export, repeat for several (default: all streams)")
+	filter := fs.String("filter", "", "CloudWatch filter pattern the events must match")
+	start := fs.String("start", "", "start of the time range: 2024-06-01T10:00, yesterday, -24h, ... (required)")
+	end := fs.String("end", "", "end of the time range, e.g. 2024-06-01T12:00 (default: now)")
+	out := fs.String("out", "", "file to write the events to (required)")
+	formatName := fs.String("format", string(aws.FormatAuto), "output format: auto (from the -out extension), raw, jsonl, csv or text")
+	gzip := fs.Bool("gzip", false, "compress the file with gzip")
+	fixtures := fs.String("fixtures", "", "read logs from a fixture file or directory instead of AWS")
+	configPath := fs.String("config", "", "configuration file (default: $XDG_CONFIG_HOME/cloudwatch-log-tui/"+config.ConfigFile+")")
+	applyFlags := config.RegisterFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: cloudwatch-log-tui export -group G -start T [-end T] -out FILE [-format auto|raw|jsonl|csv|text] [-gzip]")
+		fmt.Fprintf(fs.Output(), "exit codes: %d credentials or permissions, %d throttled, %d other errors, %d usage\n",
+			exitAuth, exitThrottled, exitError, exitUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	format, err := checkExportFlags(*group, streams, *filter, *start, *out, *formatName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return exitUsage
+	}
+	cfg, err := loadConfig(*configPath, applyFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration: %v\n", err)
+		return exitUsage
+	}
+
+	expr := *start
+	if *end != "" {
+		expr += ".." + *end
+	}
+	tr, err := state.ParseTimeRange(expr, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: invalid time range: %v\n", err)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	backend, err := newBackend(ctx, cfg, *fixtures)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return exitError
+	}
+
+	input := &aws.LogEventInput{
+		Ctx:            ctx,
+		LogGroupName:   *group,
+		LogStreamNames: streams,
+		FilterPattern:  *filter,
+		StartTime:      tr.Start,
+		EndTime:        tr.End,
+		OutputFile:     *out,
+		OutputFormat:   format,
+		Gzip:           *gzip,
+	}
+	outputFile, format := aws.ResolveOutput(input)
+
+	// a progress line is only useful on a terminal, cron logs get the summary alone
+	var progress aws.ExportProgress
+	interactive := isTerminal(os.Stderr)
+	input.OnProgress = func(p aws.ExportProgress) {
+		progress = p
+		if interactive {
+			fmt.Fprintf(os.Stderr, "\r%3.0f%%  %d pages, %d events, %d bytes", p.Percent(input), p.Pages, p.Events, p.Bytes)
+		}
+	}
+
+	began := time.Now()
+	err = backend.WriteLogEvents(input)
+	if interactive && progress.Pages > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	summary := fmt.Sprintf("%d events, %d bytes to %s (%s) in %s",
+		progress.Events, progress.Bytes, outputFile, format, time.Since(began).Round(time.Millisecond))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		fmt.Fprintf(os.Stderr, "export: stopped after %s\n", summary)
+		return exitCode(err)
+	}
+	fmt.Fprintf(os.Stderr, "exported %s\n", summary)
+	return exitOK
+}
+
+// checkExportFlags checks the flags of the export subcommand that do not need the configuration
+// and returns the output format.
+func checkExportFlags(group string, streams []string, filter, start, out, formatName string) (aws.OutputFormat, error) {
+	switch {
+	case group == "":
+		return "", errors.New("-group is required")
+	case start == "":
+		return "", errors.New("-start is required")
+	case out == "":
+		return "", errors.New("-out is required")
+	case strings.Contains(start, ".."):
+		return "", errors.New("-start is a single time, give the end with -end")
+	}
+	if err := aws.CheckLogStreamNames(streams); err != nil {
+		return "", fmt.Errorf("too many -stream: %w", err)
+	}
+	if _, err := aws.ParseFilterPattern(filter); err != nil {
+		return "", fmt.Errorf("invalid -filter: %w", err)
+	}
+	format, err := aws.ParseOutputFormat(formatName)
+	if err != nil {
+		return "", fmt.Errorf("invalid -format: %w", err)
+	}
+	return format, nil
+}
+
+// isTerminal returns true if the file is a terminal rather than a pipe or a regular file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
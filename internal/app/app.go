@@ -4,73 +4,148 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"slices"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	cwlTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
 	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/state"
 	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
 )
 
 // Constants and enum types
 const (
-	NoShortcut rune = 0
-	NextPage        = "NextPage ..."
-	PrevPage        = "... PrevPage"
+	NoShortcut    rune = 0
+	NextPage           = "NextPage ..."
+	PrevPage           = "... PrevPage"
+	AllLogStreams      = "All Log Streams"
 )
 
 // App represents the main UI application
 type App struct {
-	tvApp     *tview.Application
-	view      *view.View
-	state     *state.UIState
-	awsClient *awsr.Client
-	ctx       context.Context
+	tvApp        *tview.Application
+	view         *view.View
+	state        *state.UIState
+	awsClient    awsr.LogsBackend
+	ctx          context.Context
+	loadCtx      context.Context
+	loadCancel   context.CancelFunc
+	followCancel context.CancelFunc
+	queryCancel  context.CancelFunc
+	exportCancel context.CancelFunc
+	errorFocus   tview.Primitive
+	helpFocus    tview.Primitive
+	loadingMore  bool
+	lastLogRow   int
+
+	searchHighlighter *view.SearchHighlighter
+	// searchTimer and searchGen delay the search while typing, see SearchLater
+	searchTimer *time.Timer
+	searchGen   int
+	bookmarks   *config.Bookmarks
+	keys        *view.Keymap
+	mu          sync.RWMutex
 }
 
 // Run starts the TUI application and runs the main event loop.
 // It returns an error if the application fails to start or encounters a fatal error.
 func (a *App) Run() error {
-	return a.tvApp.SetRoot(a.view.Pages, true).
+	// keep the focus of a page opened before starting, e.g. by a bookmark
+	focus := a.tvApp.GetFocus()
+	if focus == nil {
+		focus = a.view.Widgets.LogGroup.Table
+	}
+	return a.tvApp.SetRoot(a.view.Layouts.Root, true).
 		EnableMouse(true).
-		SetFocus(a.view.Widgets.LogGroup.Table).
+		SetFocus(focus).
 		Run()
 }
 
-// New creates a new App instance with the provided context and AWS client.
+// New creates a new App instance with the provided context, logs backend and configuration.
 // It initializes the application state, view components, and key bindings.
-func New(ctx context.Context, awsClient *awsr.Client) *App {
+func New(ctx context.Context, awsClient awsr.LogsBackend, cfg *config.Config) *App {
 	app := &App{
 		tvApp:     tview.NewApplication(),
 		awsClient: awsClient,
 		ctx:       ctx,
 	}
-	app.state = state.New()
+	app.loadCtx, app.loadCancel = context.WithCancel(ctx)
+	app.state = state.New(cfg)
+	keys, err := cfg.Keymap()
+	if err != nil {
+		log.Printf("using the default keys: %v", err)
+		keys = view.DefaultKeymap()
+	}
+	app.keys = keys
 	app.view = view.New()
+	app.setExportDefaultsToGui(cfg)
+	bookmarks, err := config.NewBookmarks()
+	if err != nil {
+		log.Printf("bookmarks are not available: %v", err)
+	}
+	app.bookmarks = bookmarks
 	app.setUpKeyBindings()
+	app.setUpKeyHints()
+	app.updateLogEventStatus()
+	app.setQueryLogGroupsToGui()
+	app.updateHeader()
 	return app
 }
 
+// backend returns the logs backend currently in use.
+// It may be replaced at any time by switching the AWS profile or region.
+func (a *App) backend() awsr.LogsBackend {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.awsClient
+}
+
+// loadContext returns the context of the log group and stream loads
+// made with the backend in use, it is cancelled when the backend is replaced.
+func (a *App) loadContext() context.Context {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.loadCtx
+}
+
 // LoadLogGroups fetches log groups from AWS CloudWatch based on the navigation direction.
 // It runs asynchronously and updates the UI when the data is loaded.
 func (a *App) LoadLogGroups(direct state.Direction) {
+	ctx := a.loadContext()
 	go func() {
 		input := &awsr.LogGroupInput{
-			Ctx: a.ctx,
+			Ctx: ctx,
 		}
 		a.state.LogGroup.BeforeGet(input, direct)
-		output, err := a.awsClient.GetLogGroups(input)
+		output, err := a.backend().GetLogGroups(input)
+		if ctx.Err() != nil {
+			// the profile was switched, the result belongs to the old client
+			return
+		}
 		if err != nil {
-			log.Fatalf("unable to list tables, %v", err)
+			a.queueError("Unable to load log groups", err, func() {
+				a.LoadLogGroups(direct)
+			})
+			return
 		}
-		a.state.LogGroup.AfterGet(output, direct)
 
 		a.tvApp.QueueUpdateDraw(func() {
+			// checked again on the UI thread, where the profile is switched
+			if ctx.Err() != nil {
+				return
+			}
+			a.state.LogGroup.AfterGet(output, direct)
 			a.setLogGroupToGui(output)
 			table := a.view.Widgets.LogGroup.Table
 			a.initTableRowPosition(table, direct)
@@ -81,18 +156,30 @@ func (a *App) LoadLogGroups(direct state.Direction) {
 // LoadLogStreams fetches log streams for the selected log group based on the navigation direction.
 // It runs asynchronously and updates the UI when the data is loaded.
 func (a *App) LoadLogStreams(direct state.Direction) {
+	ctx := a.loadContext()
 	go func() {
 		input := &awsr.LogStreamInput{
-			Ctx: a.ctx,
+			Ctx: ctx,
 		}
 		a.state.LogStream.BeforeGet(input, direct)
-		output, err := a.awsClient.GetLogStreams(input)
+		output, err := a.backend().GetLogStreams(input)
+		if ctx.Err() != nil {
+			// the profile was switched, the result belongs to the old client
+			return
+		}
 		if err != nil {
-			log.Fatalf("unable to list tables, %v", err)
+			a.queueError("Unable to load log streams", err, func() {
+				a.LoadLogStreams(direct)
+			})
+			return
 		}
-		a.state.LogStream.AfterGet(output, direct)
 
 		a.tvApp.QueueUpdateDraw(func() {
+			// checked again on the UI thread, where the profile is switched
+			if ctx.Err() != nil {
+				return
+			}
+			a.state.LogStream.AfterGet(output, direct)
 			a.setLogStreamToGui(output)
 			table := a.view.Widgets.LogStream.Table
 			a.initTableRowPosition(table, direct)
@@ -101,6 +188,7 @@ func (a *App) LoadLogStreams(direct state.Direction) {
 }
 
 // LoadLogEvents fetches log events from the selected log streams within the specified time range.
+// The log groups are queried concurrently and their events merged in time order.
 // It displays the events in the log viewer and runs asynchronously.
 func (a *App) LoadLogEvents() {
 	textView := a.view.Widgets.LogEvent.ViewLog
@@ -110,42 +198,170 @@ func (a *App) LoadLogEvents() {
 		input := &awsr.LogEventInput{
 			Ctx: a.ctx,
 		}
-		a.state.LogEvent.BeforeGet(input)
-		output, err := a.awsClient.GetLogEvents(input)
-		if err != nil {
-			log.Fatalf("unnable to write logs, %v", err)
+		if err := a.state.LogEvent.BeforeGet(input, state.Home); err != nil {
+			a.logEventsFailed("Unable to load log events", err, nil)
+			return
+		}
+		inputs := a.state.LogEvent.GroupInputs(input, state.Home)
+		outputs, errs := awsr.GetLogEventsOfGroups(a.backend(), inputs)
+		err, failed := groupErrors(errs)
+		if failed == len(inputs) {
+			a.logEventsFailed("Unable to load log events", err, a.LoadLogEvents)
+			return
+		}
+		a.state.LogEvent.AfterGet(inputs, outputs, state.Home)
+		a.state.LogEvent.ResetFollow(input)
+
+		a.tvApp.QueueUpdateDraw(func() {
+			if a.state.LogEvent.GetRelativeTime() > 0 {
+				// the relative range moved forward, show where it is now
+				a.setDefaultDropDownLogEvents()
+			}
+			a.setLogEventToGui()
+			if err != nil {
+				a.showError(fmt.Sprintf("Unable to load log events of %d of %d log groups", failed, len(inputs)), err, a.LoadLogEvents)
+			}
+		})
+	}()
+}
+
+// LoadMoreLogEvents fetches the next page of log events and appends it to the log viewer.
+func (a *App) LoadMoreLogEvents() {
+	if a.loadingMore || !a.state.LogEvent.HasNext() {
+		return
+	}
+	a.loadingMore = true
+	a.updateEventCount()
+
+	go func() {
+		input := &awsr.LogEventInput{
+			Ctx: a.ctx,
+		}
+		if err := a.state.LogEvent.BeforeGet(input, state.Next); err != nil {
+			a.loadMoreFailed(err)
+			return
 		}
+		inputs := a.state.LogEvent.GroupInputs(input, state.Next)
+		outputs, errs := awsr.GetLogEventsOfGroups(a.backend(), inputs)
+		err, failed := groupErrors(errs)
+		if failed == len(inputs) {
+			a.loadMoreFailed(err)
+			return
+		}
+		dropped := a.state.LogEvent.GetDroppedEvents()
+		ok := a.state.LogEvent.AfterGet(inputs, outputs, state.Next)
+		dropped = a.state.LogEvent.GetDroppedEvents() - dropped
 
 		a.tvApp.QueueUpdateDraw(func() {
-			a.setLogEventToGui(output)
+			a.loadingMore = false
+			switch {
+			case !ok:
+			case dropped > 0:
+				a.renderTrimmedLogEvents(dropped)
+			case a.state.LogEvent.IsMerged():
+				// the page is merged into the loaded events, so render them again in place
+				a.renderLogEventsInPlace()
+			default:
+				writeLogEventsToView(a.view.Widgets.LogEvent.ViewLog, outputs[0].LogEvents, nil, a.state.LogEvent.GetMessageMode(), a.escaper())
+				a.updateSearchCount()
+				a.appendEventsToTable(outputs[0].LogEvents, nil)
+				a.lastLogRow = -1
+			}
+			a.updateEventCount()
+			if ok && err != nil {
+				a.showError(fmt.Sprintf("Unable to load more log events of %d of %d log groups", failed, len(inputs)), err, a.LoadMoreLogEvents)
+			}
 		})
 	}()
 }
 
+// scrollDownLogEvents loads the next page of log events when scrolling down
+// does not move the log viewer anymore, i.e. its bottom is reached.
+// It must be called before the scroll key is handled.
+func (a *App) scrollDownLogEvents() {
+	row, _ := a.view.Widgets.LogEvent.ViewLog.GetScrollOffset()
+	if row == a.lastLogRow {
+		a.LoadMoreLogEvents()
+	}
+	a.lastLogRow = row
+}
+
+// groupErrors joins the errors of the log groups queried concurrently
+// and returns how many of them failed.
+func groupErrors(errs []error) (error, int) {
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	return errors.Join(errs...), failed
+}
+
+// loadMoreFailed reports an error while loading the next page of log events.
+// The events loaded so far are kept in the log viewer.
+func (a *App) loadMoreFailed(err error) {
+	a.tvApp.QueueUpdateDraw(func() {
+		a.loadingMore = false
+		a.updateEventCount()
+		a.showError("Unable to load more log events", err, a.LoadMoreLogEvents)
+	})
+}
+
+// updateEventCount shows how many log events are loaded and whether more are available.
+func (a *App) updateEventCount() {
+	count := a.view.Widgets.LogEvent.EventCount
+	count.Clear()
+
+	loaded := a.state.LogEvent.GetLoadedEvents()
+	if dropped := a.state.LogEvent.GetDroppedEvents(); dropped > 0 {
+		fmt.Fprintf(count, "[yellow]oldest %d dropped[-], ", dropped)
+	}
+	switch {
+	case a.loadingMore:
+		fmt.Fprintf(count, "%d events, [yellow]loading...[-]", loaded)
+	case a.state.LogEvent.HasNext():
+		fmt.Fprintf(count, "%d events, [green]more (%s)[-]", loaded, a.keyHint(view.ActionMore))
+	default:
+		fmt.Fprintf(count, "%d events, all loaded", loaded)
+	}
+}
+
+// setDefaultDropDownLogEvents selects the preset and date/time dropdown options
+// matching the time range in the state.
 func (a *App) setDefaultDropDownLogEvents() {
-	_, startMonth, startDay, startHour, startMinute := a.state.LogEvent.GetStartTime()
-	a.view.Widgets.LogEvent.StartYear.
-		SetCurrentOption(0)
-	a.view.Widgets.LogEvent.StartMonth.
-		SetCurrentOption(startMonth - 1)
-	a.view.Widgets.LogEvent.StartDay.
-		SetCurrentOption(startDay - 1)
-	a.view.Widgets.LogEvent.StartHour.
-		SetCurrentOption(startHour)
-	a.view.Widgets.LogEvent.StartMinute.
-		SetCurrentOption(startMinute)
-
-	_, endMonth, endDay, endHour, endMinute := a.state.LogEvent.GetEndTime()
-	a.view.Widgets.LogEvent.EndYear.
-		SetCurrentOption(0)
-	a.view.Widgets.LogEvent.EndMonth.
-		SetCurrentOption(endMonth - 1)
-	a.view.Widgets.LogEvent.EndDay.
-		SetCurrentOption(endDay - 1)
-	a.view.Widgets.LogEvent.EndHour.
-		SetCurrentOption(endHour)
-	a.view.Widgets.LogEvent.EndMinute.
-		SetCurrentOption(endMinute)
+	w := a.view.Widgets.LogEvent
+
+	preset := slices.Index(view.TimePresets, view.CustomTimePreset)
+	if relative := a.state.LogEvent.GetRelativeTime(); relative > 0 {
+		for i, label := range view.TimePresets {
+			if d, err := state.ParsePreset(label); err == nil && d == relative {
+				preset = i
+			}
+		}
+	}
+	w.Preset.SetCurrentOption(preset)
+
+	startYear, startMonth, startDay, startHour, startMinute := a.state.LogEvent.GetStartTime()
+	selectDropDownValue(w.StartYear, view.StartYearDropDown, startYear)
+	selectDropDownValue(w.StartMonth, view.StartMonthDropDown, startMonth)
+	selectDropDownValue(w.StartDay, view.StartDayDropDown, startDay)
+	selectDropDownValue(w.StartHour, view.StartHourDropDown, startHour)
+	selectDropDownValue(w.StartMinute, view.StartMinuteDropDown, startMinute)
+
+	endYear, endMonth, endDay, endHour, endMinute := a.state.LogEvent.GetEndTime()
+	selectDropDownValue(w.EndYear, view.EndYearDropDown, endYear)
+	selectDropDownValue(w.EndMonth, view.EndMonthDropDown, endMonth)
+	selectDropDownValue(w.EndDay, view.EndDayDropDown, endDay)
+	selectDropDownValue(w.EndHour, view.EndHourDropDown, endHour)
+	selectDropDownValue(w.EndMinute, view.EndMinuteDropDown, endMinute)
+}
+
+// selectDropDownValue selects the option of a date/time dropdown showing value.
+func selectDropDownValue(dd *tview.DropDown, widget view.Widget, value int) {
+	if i := view.OptionIndex(widget, strconv.Itoa(value)); i >= 0 {
+		dd.SetCurrentOption(i)
+	}
 }
 
 // setLogGroupToGui sets the log group data to the GUI
@@ -154,6 +370,7 @@ func (a *App) setLogGroupToGui(aw *awsr.LogGroupOutput) {
 	lgTable.Clear()
 
 	headers := []string{
+		"Selected",
 		"Name",
 		"RetentionDays",
 		"StoredBytes",
@@ -173,7 +390,7 @@ func (a *App) setLogGroupToGui(aw *awsr.LogGroupOutput) {
 	row++
 
 	if a.state.LogGroup.HasPrev() {
-		lgTable.SetCell(row, 0, tview.NewTableCell(PrevPage).
+		lgTable.SetCell(row, 1, tview.NewTableCell(PrevPage).
 			SetTextColor(tcell.ColorLightSalmon).
 			SetMaxWidth(1).
 			SetExpansion(7))
@@ -185,18 +402,28 @@ func (a *App) setLogGroupToGui(aw *awsr.LogGroupOutput) {
 		// int32 to string
 		retentionDays := fmt.Sprintf("%d", aws.ToInt32(lg.RetentionInDays))
 		storedBytes := fmt.Sprintf("%d", aws.ToInt64(lg.StoredBytes))
+		selectedMark := ""
 
-		lgTable.SetCell(row, 0, tview.NewTableCell(lgName).
+		if slices.Contains(a.state.LogGroup.GetSelected(), lgName) {
+			selectedMark = "x"
+		}
+
+		lgTable.SetCell(row, 0, tview.NewTableCell(selectedMark).
+			SetTextColor(tcell.ColorLightGreen).
+			SetMaxWidth(1).
+			SetExpansion(1))
+
+		lgTable.SetCell(row, 1, tview.NewTableCell(lgName).
 			SetTextColor(tcell.ColorLightGreen).
 			SetMaxWidth(1).
 			SetExpansion(7))
 
-		lgTable.SetCell(row, 1, tview.NewTableCell(retentionDays).
+		lgTable.SetCell(row, 2, tview.NewTableCell(retentionDays).
 			SetTextColor(tcell.ColorLightGreen).
 			SetMaxWidth(1).
 			SetExpansion(1))
 
-		lgTable.SetCell(row, 2, tview.NewTableCell(storedBytes).
+		lgTable.SetCell(row, 3, tview.NewTableCell(storedBytes).
 			SetTextColor(tcell.ColorLightGreen).
 			SetMaxWidth(1).
 			SetExpansion(1))
@@ -205,11 +432,21 @@ func (a *App) setLogGroupToGui(aw *awsr.LogGroupOutput) {
 	}
 
 	if a.state.LogGroup.HasNext() {
-		lgTable.SetCell(row, 0, tview.NewTableCell(NextPage).
+		lgTable.SetCell(row, 1, tview.NewTableCell(NextPage).
 			SetTextColor(tcell.ColorLightSteelBlue).
 			SetMaxWidth(1).
 			SetExpansion(7))
 	}
+	a.setLogGroupTitle()
+}
+
+// setLogGroupTitle shows the number of marked log groups in the title of the log group table.
+func (a *App) setLogGroupTitle() {
+	title := "Log Groups"
+	if n := len(a.state.LogGroup.GetSelected()); n > 0 {
+		title = fmt.Sprintf("%s (%d selected, %s opens them)", title, n, a.keyHint(view.ActionSelect))
+	}
+	a.view.Widgets.LogGroup.Table.SetTitle(title)
 }
 
 func (a *App) setLogStreamToGui(aw *awsr.LogStreamOutput) {
@@ -262,7 +499,7 @@ func (a *App) setLogStreamToGui(aw *awsr.LogStreamOutput) {
 		SetMaxWidth(1).
 		SetExpansion(1))
 
-	lsTable.SetCell(row, 1, tview.NewTableCell("All Log Streams").
+	lsTable.SetCell(row, 1, tview.NewTableCell(AllLogStreams).
 		SetTextColor(tcell.ColorLightGreen).
 		SetMaxWidth(1).
 		SetExpansion(10))
@@ -285,7 +522,7 @@ func (a *App) setLogStreamToGui(aw *awsr.LogStreamOutput) {
 		firstEventTime := time.UnixMilli(aws.ToInt64(ls.FirstEventTimestamp)).Local().Format("2006-01-02 15:04:05")
 		selectedMark := ""
 
-		if slices.Contains(a.state.LogEvent.GetLogStreamsSelected(), lsName) {
+		if slices.Contains(a.state.LogStream.GetSelected(), lsName) {
 			selectedMark = "x"
 		}
 
@@ -334,20 +571,130 @@ func (a *App) setLogStreamToGui(aw *awsr.LogStreamOutput) {
 			SetMaxWidth(1).
 			SetExpansion(2))
 	}
+	a.setLogStreamTitle()
+}
+
+// setLogStreamTitle shows the number of marked log streams in the title of the log stream table.
+func (a *App) setLogStreamTitle() {
+	title := "Log Streams"
+	if n := len(a.state.LogStream.GetSelected()); n > 0 {
+		title = fmt.Sprintf("%s (%d selected, %s opens them)", title, n, a.keyHint(view.ActionSelect))
+	}
+	a.view.Widgets.LogStream.Table.SetTitle(title)
+}
+
+// pageLogStreams returns the names of the log streams shown in the log stream table.
+func (a *App) pageLogStreams() []string {
+	lsTable := a.view.Widgets.LogStream.Table
+	var names []string
+	for row := 1; row < lsTable.GetRowCount(); row++ {
+		if name, ok := logStreamAt(lsTable, row); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// logStreamAt returns the name of the log stream in a row of the log stream table,
+// or false if the row is not a log stream, like the paging rows or "All Log Streams".
+func logStreamAt(lsTable *tview.Table, row int) (string, bool) {
+	name := lsTable.GetCell(row, 1).Text
+	switch name {
+	case "", AllLogStreams, NextPage, PrevPage:
+		return "", false
+	}
+	return name, true
+}
+
+// refreshLogStreamMarks updates the selected column of the log stream table and its title.
+func (a *App) refreshLogStreamMarks() {
+	lsTable := a.view.Widgets.LogStream.Table
+	selected := a.state.LogStream.GetSelected()
+	for row := 1; row < lsTable.GetRowCount(); row++ {
+		name, ok := logStreamAt(lsTable, row)
+		if !ok {
+			continue
+		}
+		selectedMark := ""
+		if slices.Contains(selected, name) {
+			selectedMark = "x"
+		}
+		lsTable.GetCell(row, 0).SetText(selectedMark)
+	}
+	a.setLogStreamTitle()
 }
 
-func (a *App) setLogEventToGui(output *awsr.LogEventOutput) {
+// setLogEventToGui renders the settings and the loaded log events in the log viewer.
+func (a *App) setLogEventToGui() {
+	a.updateEventCount()
+	a.setEventTableToGui()
+	a.setLogViewToGui()
+}
+
+// setLogViewToGui renders the settings and the loaded log events in the text view alone,
+// e.g. to highlight another search, leaving the event table and its selection as they are.
+func (a *App) setLogViewToGui() {
 	textView := a.view.Widgets.LogEvent.ViewLog
 	textView.Clear()
-	a.state.LogEvent.Print(textView)
-
-	if len(output.LogEvents) == 0 {
-		fmt.Fprintf(textView, "no events\n")
+	a.state.LogEvent.Print(textView, a.keys)
+	a.lastLogRow = -1
+
+	events := a.state.LogEvent.GetEvents()
+	if len(events) == 0 {
+		a.searchHighlighter = nil
+		a.highlightMatch()
+		if a.state.LogEvent.HasNext() {
+			fmt.Fprintf(textView, "no events in the first page, press '%s' to load more\n", a.keyHint(view.ActionMore))
+		} else {
+			fmt.Fprintf(textView, "no events\n")
+		}
 		return
 	}
 
-	for _, event := range output.LogEvents {
-		fmt.Fprintf(textView, "%s", aws.ToString(event.Message))
+	var groups []string
+	if a.state.LogEvent.IsMerged() {
+		groups = a.state.LogEvent.GetEventGroups()
+	}
+	writeLogEventsToView(textView, events, groups, a.state.LogEvent.GetMessageMode(), a.newEscaper())
+	a.highlightMatch()
+}
+
+// ToggleMessageMode switches how log messages are rendered and renders them again.
+func (a *App) ToggleMessageMode() {
+	a.state.LogEvent.NextMessageMode()
+	a.renderLogEventsInPlace()
+}
+
+// renderLogEventsInPlace renders the log events again, keeping the scroll position
+// of the log viewer and the selection of the event table.
+func (a *App) renderLogEventsInPlace() {
+	row, _ := a.view.Widgets.LogEvent.ViewLog.GetScrollOffset()
+	selected, _ := a.view.Widgets.LogEvent.EventTable.GetSelection()
+	a.setLogEventToGui()
+	a.view.Widgets.LogEvent.ViewLog.ScrollTo(row, 0)
+	a.view.Widgets.LogEvent.EventTable.Select(selected, 0)
+}
+
+// renderTrimmedLogEvents renders the log events again after the oldest were dropped,
+// keeping the same event selected in the table.
+func (a *App) renderTrimmedLogEvents(dropped int) {
+	table := a.view.Widgets.LogEvent.EventTable
+	selected, _ := table.GetSelection()
+	a.renderLogEventsInPlace()
+	table.Select(max(selected-dropped, 1), 0)
+}
+
+// writeLogEventsToView appends the messages of the events to the text view,
+// rendered according to the message mode and escaped with escape.
+// If groups is not nil, each message is prefixed with the log group of its event.
+func writeLogEventsToView(textView *tview.TextView, events []cwlTypes.FilteredLogEvent, groups []string, mode view.MessageMode, escape view.Escaper) {
+	w := textView.BatchWriter()
+	defer w.Close()
+	for i, event := range events {
+		if i < len(groups) {
+			fmt.Fprintf(w, "[::d]%s[::-] ", tview.Escape(groups[i]))
+		}
+		fmt.Fprint(w, view.FormatMessage(aws.ToString(event.Message), mode, escape))
 	}
 }
 
@@ -367,24 +714,13 @@ func (a *App) initTableRowPosition(table *tview.Table, direct state.Direction) {
 	table.Select(selectRow, 0)
 }
 
-// SaveLogEvents writes the log events to a file based on the current query parameters.
-// It runs asynchronously and displays a completion message when finished.
-func (a *App) SaveLogEvents() {
-	textView := a.view.Widgets.LogEvent.ViewLog
-	textView.Clear()
-	fmt.Fprintln(textView, "Now Loading... ")
-	go func() {
-		input := &awsr.LogEventInput{
-			Ctx: a.ctx,
-		}
-		a.state.LogEvent.BeforeGet(input)
-		err := a.awsClient.WriteLogEvents(input)
-		if err != nil {
-			log.Fatalf("unnable to write logs, %v", err)
-		}
-		a.tvApp.QueueUpdateDraw(func() {
-			textView.Clear()
-			fmt.Fprintln(textView, "Finished writing log events.")
-		})
-	}()
+// logEventsFailed replaces the loading message of the log viewer
+// and shows the error from a background goroutine.
+func (a *App) logEventsFailed(title string, err error, retry func()) {
+	a.tvApp.QueueUpdateDraw(func() {
+		textView := a.view.Widgets.LogEvent.ViewLog
+		textView.Clear()
+		fmt.Fprintf(textView, "%s.\n", title)
+		a.showError(title, err, retry)
+	})
 }
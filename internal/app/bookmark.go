@@ -0,0 +1,252 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/state"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// bookmarkKeys returns the keys of the bookmarks page for its status line.
+func (a *App) bookmarkKeys() string {
+	return fmt.Sprintf("%s open, %s rename, %s delete, %s back",
+		a.keyHint(view.ActionSelect), a.keyHint(view.ActionRename), a.keyHint(view.ActionDelete), a.keyHint(view.ActionBack))
+}
+
+// OpenBookmarks lists the saved bookmarks and shows the bookmarks page.
+// Closing it returns to returnPage.
+func (a *App) OpenBookmarks(returnPage view.Page) {
+	if a.bookmarks == nil {
+		a.showError("Unable to open bookmarks", fmt.Errorf("the config directory is not available"), nil)
+		return
+	}
+	a.state.Bookmark.SetReturnPage(returnPage)
+	a.state.Bookmark.EndEdit()
+	if !a.reloadBookmarks("") {
+		return
+	}
+	a.setBookmarkStatus(a.bookmarkKeys())
+
+	a.view.Pages.SwitchToPage(view.PageNames[view.BookmarkPage])
+	a.tvApp.SetFocus(a.view.Widgets.Bookmark.Table)
+}
+
+// CloseBookmarks returns to the page the bookmarks page was opened from.
+func (a *App) CloseBookmarks() {
+	a.state.Bookmark.EndEdit()
+	a.view.Widgets.Bookmark.Name.SetText("")
+
+	page := a.state.Bookmark.GetReturnPage()
+	a.view.Pages.SwitchToPage(view.PageNames[page])
+	if page == view.LogEventPage {
+		a.tvApp.SetFocus(a.logEventsView())
+	} else {
+		a.tvApp.SetFocus(a.view.Widgets.LogGroup.Table)
+	}
+}
+
+// AddBookmark opens the bookmarks page to name a bookmark of the current log event view.
+func (a *App) AddBookmark() {
+	a.OpenBookmarks(view.LogEventPage)
+	if a.bookmarks == nil {
+		return
+	}
+
+	name := a.state.LogEvent.ToBookmark("").LogGroupName
+	name = name[strings.LastIndex(name, "/")+1:]
+	a.startBookmarkEdit(state.BookmarkAdd, "", name)
+	a.setBookmarkStatus(fmt.Sprintf("%s saves the current view under this name, an existing bookmark is replaced. %s cancels",
+		a.keyHint(view.ActionSelect), a.keyHint(view.ActionBack)))
+}
+
+// RenameBookmark starts renaming the selected bookmark.
+func (a *App) RenameBookmark() {
+	b, ok := a.selectedBookmark()
+	if !ok {
+		return
+	}
+	a.startBookmarkEdit(state.BookmarkRename, b.Name, b.Name)
+	a.setBookmarkStatus(fmt.Sprintf("%s renames %q, %s cancels",
+		a.keyHint(view.ActionSelect), tview.Escape(b.Name), a.keyHint(view.ActionBack)))
+}
+
+// startBookmarkEdit fills the name input and moves the focus to it.
+func (a *App) startBookmarkEdit(edit state.BookmarkEdit, renaming string, name string) {
+	a.state.Bookmark.StartEdit(edit, renaming)
+	a.view.Widgets.Bookmark.Name.SetText(name)
+	a.tvApp.SetFocus(a.view.Widgets.Bookmark.Name)
+}
+
+// SubmitBookmarkName saves a new bookmark or renames one with the typed name.
+func (a *App) SubmitBookmarkName() {
+	name := strings.TrimSpace(a.view.Widgets.Bookmark.Name.GetText())
+	edit, renaming := a.state.Bookmark.GetEdit()
+
+	var err error
+	var done string
+	switch edit {
+	case state.BookmarkAdd:
+		err = a.bookmarks.Put(a.state.LogEvent.ToBookmark(name))
+		done = fmt.Sprintf("[green]Saved bookmark %q[-]. %s", tview.Escape(name), a.bookmarkKeys())
+	case state.BookmarkRename:
+		err = a.bookmarks.Rename(renaming, name)
+		done = fmt.Sprintf("[green]Renamed %q to %q[-]. %s", tview.Escape(renaming), tview.Escape(name), a.bookmarkKeys())
+	default:
+		return
+	}
+	if err != nil {
+		a.setBookmarkStatus(fmt.Sprintf("[red]%s[-]", tview.Escape(err.Error())))
+		return
+	}
+
+	a.CancelBookmarkEdit()
+	a.reloadBookmarks(name)
+	a.setBookmarkStatus(done)
+}
+
+// CancelBookmarkEdit clears the name input and returns to the bookmark table.
+func (a *App) CancelBookmarkEdit() {
+	a.state.Bookmark.EndEdit()
+	a.view.Widgets.Bookmark.Name.SetText("")
+	a.setBookmarkStatus(a.bookmarkKeys())
+	a.tvApp.SetFocus(a.view.Widgets.Bookmark.Table)
+}
+
+// DeleteBookmark deletes the selected bookmark once the deletion is confirmed
+// by asking a second time.
+func (a *App) DeleteBookmark() {
+	b, ok := a.selectedBookmark()
+	if !ok {
+		return
+	}
+	if !a.state.Bookmark.ConfirmDelete(b.Name) {
+		a.setBookmarkStatus(fmt.Sprintf("[yellow]Press %s again to delete %q[-]", a.keyHint(view.ActionDelete), tview.Escape(b.Name)))
+		return
+	}
+	if err := a.bookmarks.Delete(b.Name); err != nil {
+		a.setBookmarkStatus(fmt.Sprintf("[red]%s[-]", tview.Escape(err.Error())))
+		return
+	}
+	a.reloadBookmarks("")
+	a.setBookmarkStatus(fmt.Sprintf("[green]Deleted bookmark %q[-]. %s", tview.Escape(b.Name), a.bookmarkKeys()))
+}
+
+// OpenSelectedBookmark opens the log event view of the selected bookmark.
+func (a *App) OpenSelectedBookmark() {
+	b, ok := a.selectedBookmark()
+	if !ok {
+		return
+	}
+	if err := a.OpenBookmark(b.Name); err != nil {
+		a.showError("Unable to open bookmark", err, nil)
+	}
+}
+
+// OpenBookmark restores the log group, streams, filter pattern, time range and
+// output format of the named bookmark and loads its log events.
+func (a *App) OpenBookmark(name string) error {
+	if a.bookmarks == nil {
+		return fmt.Errorf("the config directory is not available")
+	}
+	b, err := a.bookmarks.Get(name)
+	if err != nil {
+		return err
+	}
+	return a.OpenView(b)
+}
+
+// OpenView restores the log groups, streams, filter pattern, time range and
+// output format of a view, like a bookmark or a link given on the command line,
+// and loads its log events.
+func (a *App) OpenView(b config.Bookmark) error {
+	if err := a.state.LogEvent.ApplyBookmark(b); err != nil {
+		return err
+	}
+	a.StopFollow()
+	a.state.LogStream.SetLogGroupSelected(b.LogGroupName)
+	a.state.Bookmark.EndEdit()
+
+	w := a.view.Widgets.LogEvent
+	w.FilterPatern.SetText(b.FilterPattern)
+	if i := slices.Index(view.OutputFormats, string(a.state.LogEvent.GetOutputFormat())); i >= 0 {
+		w.OutputFormat.SetCurrentOption(i)
+	}
+	a.setDefaultDropDownLogEvents()
+	a.LoadLogEvents()
+
+	a.view.Pages.SwitchToPage(view.PageNames[view.LogEventPage])
+	a.tvApp.SetFocus(a.logEventsView())
+	return nil
+}
+
+// selectedBookmark returns the bookmark selected in the table.
+func (a *App) selectedBookmark() (config.Bookmark, bool) {
+	row, _ := a.view.Widgets.Bookmark.Table.GetSelection()
+	// the first row is the header
+	return a.state.Bookmark.GetBookmark(row - 1)
+}
+
+// reloadBookmarks reads the bookmarks file and shows the bookmarks in the table,
+// selecting the named bookmark if given. It returns false if the file could not be read.
+func (a *App) reloadBookmarks(selected string) bool {
+	bookmarks, err := a.bookmarks.List()
+	if err != nil {
+		a.showError("Unable to read bookmarks", err, nil)
+		return false
+	}
+	a.state.Bookmark.SetBookmarks(bookmarks)
+	a.setBookmarksToGui(selected)
+	return true
+}
+
+// setBookmarksToGui fills the bookmark table from the state.
+func (a *App) setBookmarksToGui(selected string) {
+	table := a.view.Widgets.Bookmark.Table
+	row, _ := table.GetSelection()
+	table.Clear()
+
+	for col, header := range []string{"Name", "Log Group", "Streams", "Filter", "Time Range", "Format"} {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tview.Styles.SecondaryTextColor).
+			SetSelectable(false))
+	}
+
+	bookmarks := a.state.Bookmark.GetBookmarks()
+	for i, b := range bookmarks {
+		streams := "ALL"
+		if len(b.LogStreams) > 0 {
+			streams = strings.Join(b.LogStreams, ", ")
+		}
+		format := b.OutputFormat
+		if format == "" {
+			format = "auto"
+		}
+		for col, text := range []string{b.Name, strings.Join(b.Groups(), ", "), streams, b.FilterPattern, b.TimeRange(), format} {
+			table.SetCell(i+1, col, tview.NewTableCell(tview.Escape(text)).SetMaxWidth(40))
+		}
+		if b.Name == selected {
+			row = i + 1
+		}
+	}
+
+	if len(bookmarks) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell(fmt.Sprintf("No bookmarks yet, press %s in the log view to add one", a.keyHint(view.ActionBookmark))).
+			SetSelectable(false))
+		table.SetTitle(fmt.Sprintf("%s (%s)", view.WidgetNames[view.BookmarkTable], tview.Escape(a.bookmarks.Path())))
+		return
+	}
+	table.SetTitle(fmt.Sprintf("%s (%d)", view.WidgetNames[view.BookmarkTable], len(bookmarks)))
+	table.Select(min(max(row, 1), len(bookmarks)), 0)
+}
+
+// setBookmarkStatus replaces the text of the bookmarks status line.
+func (a *App) setBookmarkStatus(text string) {
+	status := a.view.Widgets.Bookmark.Status
+	status.Clear()
+	fmt.Fprint(status, text)
+}
@@ -0,0 +1,56 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// clipboardCommands are the commands copying their input to the clipboard of a local desktop,
+// with the environment variable telling the desktop is available.
+var clipboardCommands = []struct {
+	env  string
+	name string
+	args []string
+}{
+	{"WAYLAND_DISPLAY", "wl-copy", nil},
+	{"DISPLAY", "xclip", []string{"-selection", "clipboard"}},
+}
+
+// copyToClipboard copies text to the clipboard and returns how it was copied.
+// On a local desktop wl-copy or xclip is used when installed, otherwise the text is sent
+// to the terminal with an OSC 52 escape sequence, which also works over SSH.
+func copyToClipboard(text string) (string, error) {
+	for _, c := range clipboardCommands {
+		if os.Getenv(c.env) == "" {
+			continue
+		}
+		path, err := exec.LookPath(c.name)
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, c.args...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err == nil {
+			return c.name, nil
+		}
+	}
+	return "OSC 52", copyOSC52(text)
+}
+
+// copyOSC52 copies text to the clipboard of the terminal with an OSC 52 escape sequence.
+// It is written to the terminal directly since the screen does not pass it on.
+// Terminals that do not support OSC 52 ignore it, so no error does not mean the text was copied.
+func copyOSC52(text string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("no terminal to copy to: %w", err)
+	}
+	defer tty.Close()
+
+	_, err = fmt.Fprintf(tty, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
@@ -0,0 +1,78 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rivo/tview"
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// eventLinkWindow is the time range of the console link of a single event, starting at the event.
+const eventLinkWindow = time.Minute
+
+// CopyConsoleLink copies the CloudWatch console URL of the selected event of the table view,
+// or of the log event view otherwise, and shows it below the filter pattern and logs it
+// in case the terminal cannot copy it.
+func (a *App) CopyConsoleLink() {
+	link, err := a.consoleLink()
+	if err != nil {
+		a.setFilterStatus(fmt.Sprintf("[red]Console link: %s[-]", tview.Escape(err.Error())))
+		return
+	}
+	url := link.URL()
+	// a long link does not fit in the status line, the log file has all of it
+	log.Printf("console link: %s", url)
+	how, err := copyToClipboard(url)
+	if err != nil {
+		a.setFilterStatus(fmt.Sprintf("[yellow]Console link (%s):[-] %s", tview.Escape(err.Error()), tview.Escape(url)))
+		return
+	}
+	a.setFilterStatus(fmt.Sprintf("[green]Console link copied with %s:[-] %s", how, tview.Escape(url)))
+}
+
+// consoleLink returns the console view of the selected event of the table view,
+// or of the log groups, streams, filter pattern and time range of the log event view.
+func (a *App) consoleLink() (*awsr.ConsoleLink, error) {
+	link := &awsr.ConsoleLink{}
+	if client, ok := a.backend().(*awsr.Client); ok {
+		link.Region = client.Options().Region
+	}
+
+	if a.state.LogEvent.IsTableView() {
+		row, _ := a.view.Widgets.LogEvent.EventTable.GetSelection()
+		if event, ok := a.state.LogEvent.GetEvent(row - 1); ok {
+			link.LogGroupName = a.state.LogEvent.GetEventGroup(row - 1)
+			if link.LogGroupName == "" {
+				link.LogGroupName = a.state.LogEvent.ToBookmark("").LogGroupName
+			}
+			link.LogStreamNames = []string{aws.ToString(event.LogStreamName)}
+			link.Start = time.UnixMilli(aws.ToInt64(event.Timestamp))
+			link.End = link.Start.Add(eventLinkWindow)
+			return link, nil
+		}
+	}
+
+	b := a.state.LogEvent.ToBookmark("")
+	if len(b.Groups()) > 1 {
+		// the error is escaped when it is shown
+		return nil, fmt.Errorf("the console shows one log group at a time, select an event in the table (%s) to link its group",
+			a.keys.Hint(view.ActionToggleView))
+	}
+	if b.LogGroupName == "" {
+		return nil, errors.New("no log group selected")
+	}
+	link.LogGroupName = b.LogGroupName
+	link.LogStreamNames = b.LogStreams
+	link.FilterPattern = b.FilterPattern
+	link.Relative = a.state.LogEvent.GetRelativeTime()
+	if link.Relative == 0 {
+		link.Start, link.End = b.Start, b.End
+	}
+	return link, nil
+}
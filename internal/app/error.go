@@ -0,0 +1,67 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/rivo/tview"
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// Labels of the error modal buttons
+const (
+	RetryButton = "Retry"
+	CloseButton = "Close"
+)
+
+// showError displays a dismissable error modal on top of the current page.
+// If retry is not nil, a Retry button is offered that runs it after closing the modal.
+// It must be called from the UI goroutine.
+func (a *App) showError(title string, err error, retry func()) {
+	log.Printf("%s, %v", title, err)
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s\n\n", title)
+	code, requestID := awsr.ErrorDetails(err)
+	if code != "" {
+		fmt.Fprintf(&text, "Code: %s\n", code)
+	}
+	if requestID != "" {
+		fmt.Fprintf(&text, "Request ID: %s\n", requestID)
+	}
+	fmt.Fprintf(&text, "\n%s", err)
+
+	buttons := []string{CloseButton}
+	if retry != nil {
+		buttons = []string{RetryButton, CloseButton}
+	}
+
+	modal := a.view.Widgets.Error.Modal
+	// keep the focus of the page below when an error replaces another one
+	if !modal.HasFocus() {
+		a.errorFocus = a.tvApp.GetFocus()
+	}
+	modal.ClearButtons().
+		SetText(tview.Escape(text.String())).
+		AddButtons(buttons).
+		SetDoneFunc(func(_ int, label string) {
+			a.view.Pages.HidePage(view.PageNames[view.ErrorPage])
+			a.tvApp.SetFocus(a.errorFocus)
+			if label == RetryButton {
+				retry()
+			}
+		})
+
+	a.view.Pages.ShowPage(view.PageNames[view.ErrorPage])
+	a.tvApp.SetFocus(modal)
+}
+
+// queueError shows an error modal from a background goroutine.
+func (a *App) queueError(title string, err error, retry func()) {
+	a.tvApp.QueueUpdateDraw(func() {
+		a.showError(title, err, retry)
+	})
+}
@@ -0,0 +1,182 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwlTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// eventTimeLayout is the layout of event times in the event table.
+const eventTimeLayout = "2006-01-02 15:04:05.000"
+
+// ToggleEventView switches between the text and the table view of log events.
+func (a *App) ToggleEventView() {
+	a.StopVisual()
+	if a.state.LogEvent.ToggleTableView() {
+		a.view.Layouts.LogEventViews.SwitchToPage(view.WidgetNames[view.EventTable])
+	} else {
+		a.view.Layouts.LogEventViews.SwitchToPage(view.WidgetNames[view.ViewLog])
+	}
+	a.tvApp.SetFocus(a.logEventsView())
+}
+
+// ToggleUTC switches the event times of the table view between local time and UTC.
+func (a *App) ToggleUTC() {
+	a.state.LogEvent.ToggleUTC()
+	row, _ := a.view.Widgets.LogEvent.EventTable.GetSelection()
+	a.setEventTableToGui()
+	a.view.Widgets.LogEvent.EventTable.Select(row, 0)
+	a.refreshVisualRows()
+}
+
+// logEventsView returns the widget currently showing the log events.
+func (a *App) logEventsView() tview.Primitive {
+	if a.state.LogEvent.IsTableView() {
+		return a.view.Widgets.LogEvent.EventTable
+	}
+	return a.view.Widgets.LogEvent.ViewLog
+}
+
+// setEventTableToGui fills the event table with the loaded log events.
+func (a *App) setEventTableToGui() {
+	table := a.view.Widgets.LogEvent.EventTable
+	table.Clear()
+
+	timeHeader := "Time (Local)"
+	if a.state.LogEvent.IsUTC() {
+		timeHeader = "Time (UTC)"
+	}
+	headers := []string{
+		timeHeader,
+		"Lag",
+		"Stream",
+		"Message",
+	}
+	var groups []string
+	if a.state.LogEvent.IsMerged() {
+		headers = slices.Insert(headers, 2, "Group")
+		groups = a.state.LogEvent.GetEventGroups()
+	}
+
+	for i, header := range headers {
+		table.SetCell(0, i, &tview.TableCell{
+			Text:            header,
+			NotSelectable:   true,
+			Align:           tview.AlignLeft,
+			Color:           tcell.ColorWhite,
+			BackgroundColor: tcell.ColorDefault,
+			Attributes:      tcell.AttrBold,
+		})
+	}
+
+	a.appendEventsToTable(a.state.LogEvent.GetEvents(), groups)
+	table.Select(1, 0)
+	table.ScrollToBeginning()
+	a.setEventDetailToGui(1)
+}
+
+// appendEventsToTable adds a row per event to the event table.
+// If groups is not nil, the log group of each event is shown in a column of its own.
+func (a *App) appendEventsToTable(events []cwlTypes.FilteredLogEvent, groups []string) {
+	table := a.view.Widgets.LogEvent.EventTable
+	utc := a.state.LogEvent.IsUTC()
+
+	// keep the last row selected while following, like the text view scrolls to the end
+	row, _ := table.GetSelection()
+	atEnd := row == table.GetRowCount()-1
+
+	for i, event := range events {
+		r := table.GetRowCount()
+		col := 0
+		table.SetCell(r, col, tview.NewTableCell(formatEventTime(event.Timestamp, utc)).
+			SetTextColor(tcell.ColorLightGreen))
+		col++
+		table.SetCell(r, col, tview.NewTableCell(formatLag(event)).
+			SetTextColor(tcell.ColorLightGreen).
+			SetAlign(tview.AlignRight))
+		col++
+		if groups != nil {
+			table.SetCell(r, col, tview.NewTableCell(tview.Escape(groups[i])).
+				SetTextColor(tcell.ColorLightGreen).
+				SetMaxWidth(1).
+				SetExpansion(1))
+			col++
+		}
+		table.SetCell(r, col, tview.NewTableCell(tview.Escape(aws.ToString(event.LogStreamName))).
+			SetTextColor(tcell.ColorLightGreen).
+			SetMaxWidth(1).
+			SetExpansion(1))
+		col++
+		table.SetCell(r, col, tview.NewTableCell(tview.Escape(messagePreview(aws.ToString(event.Message)))).
+			SetTextColor(tcell.ColorLightGreen).
+			SetMaxWidth(1).
+			SetExpansion(4))
+	}
+
+	if atEnd && row > 0 && a.state.LogEvent.IsFollowing() {
+		table.Select(table.GetRowCount()-1, 0)
+	}
+}
+
+// setEventDetailToGui shows the full message and metadata of the event in the given table row.
+func (a *App) setEventDetailToGui(row int) {
+	detail := a.view.Widgets.LogEvent.EventDetail
+	detail.Clear()
+	detail.ScrollToBeginning()
+
+	event, ok := a.state.LogEvent.GetEvent(row - 1)
+	if !ok {
+		return
+	}
+
+	fmt.Fprintf(detail, "[white::b]Time:[-::-]      %s / %s\n",
+		formatEventTime(event.Timestamp, false),
+		formatEventTime(event.Timestamp, true)+" UTC")
+	fmt.Fprintf(detail, "[white::b]Ingested:[-::-]  %s (lag %s)\n",
+		formatEventTime(event.IngestionTime, false),
+		formatLag(event))
+	if a.state.LogEvent.IsMerged() {
+		fmt.Fprintf(detail, "[white::b]Group:[-::-]     %s\n", tview.Escape(a.state.LogEvent.GetEventGroup(row-1)))
+	}
+	fmt.Fprintf(detail, "[white::b]Stream:[-::-]    %s\n", tview.Escape(aws.ToString(event.LogStreamName)))
+	fmt.Fprintf(detail, "[white::b]Event ID:[-::-]  %s\n\n", tview.Escape(aws.ToString(event.EventId)))
+	fmt.Fprint(detail, view.FormatMessage(aws.ToString(event.Message), a.state.LogEvent.GetMessageMode(), nil))
+}
+
+// formatEventTime formats an epoch time in milliseconds in local time or UTC.
+func formatEventTime(ms *int64, utc bool) string {
+	if ms == nil {
+		return ""
+	}
+	t := time.UnixMilli(*ms)
+	if utc {
+		t = t.UTC()
+	}
+	return t.Format(eventTimeLayout)
+}
+
+// formatLag formats the delay between the time of an event and its ingestion.
+func formatLag(event cwlTypes.FilteredLogEvent) string {
+	if event.Timestamp == nil || event.IngestionTime == nil {
+		return ""
+	}
+	lag := time.Duration(*event.IngestionTime-*event.Timestamp) * time.Millisecond
+	return lag.String()
+}
+
+// messagePreview returns the first line of a message.
+func messagePreview(message string) string {
+	message = strings.TrimSpace(message)
+	if first, _, ok := strings.Cut(message, "\n"); ok {
+		return first + " ..."
+	}
+	return message
+}
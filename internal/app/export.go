@@ -0,0 +1,130 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rivo/tview"
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// Labels of the save button
+const (
+	SaveButtonLabel   = "Save Button"
+	CancelButtonLabel = "Cancel Button"
+)
+
+// progressBarWidth is the number of cells of the export progress bar.
+const progressBarWidth = 40
+
+// SaveLogEvents writes the log events to a file based on the current query parameters.
+// It runs asynchronously with its own context and shows its progress in the log viewer.
+// While it runs, the save button cancels it instead.
+func (a *App) SaveLogEvents() {
+	if a.exportCancel != nil {
+		a.CancelSave()
+		return
+	}
+
+	input := &awsr.LogEventInput{}
+	if err := a.state.LogEvent.BeforeExport(input); err != nil {
+		a.showError("Unable to save log events", err, nil)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.exportCancel = cancel
+	input.Ctx = ctx
+	input.OnProgress = func(progress awsr.ExportProgress) {
+		a.state.LogEvent.SetExportProgress(progress)
+		a.tvApp.QueueUpdateDraw(func() {
+			a.setExportProgressToGui(input, "Saving")
+		})
+	}
+
+	a.view.Widgets.LogEvent.SaveEventLog.SetLabel(CancelButtonLabel)
+	a.setExportProgressToGui(input, "Saving")
+
+	go func() {
+		err := a.backend().WriteLogEvents(input)
+		cancelled := ctx.Err() != nil
+
+		a.tvApp.QueueUpdateDraw(func() {
+			cancel()
+			a.exportCancel = nil
+			a.view.Widgets.LogEvent.SaveEventLog.SetLabel(SaveButtonLabel)
+			a.state.LogEvent.AfterExport(input)
+
+			switch {
+			case err == nil:
+				a.setExportProgressToGui(input, "Finished saving")
+			case cancelled:
+				a.setExportProgressToGui(input, "Cancelled saving")
+			default:
+				a.setExportProgressToGui(input, "Failed saving")
+				a.showError("Unable to save log events", err, a.SaveLogEvents)
+			}
+		})
+	}()
+}
+
+// CancelSave cancels the running export, if any.
+// Pages already written are kept and the export can be resumed by saving again.
+func (a *App) CancelSave() {
+	if a.exportCancel != nil {
+		a.exportCancel()
+	}
+}
+
+// setExportProgressToGui shows the progress of an export in the log viewer.
+func (a *App) setExportProgressToGui(input *awsr.LogEventInput, title string) {
+	progress := a.state.LogEvent.GetExportProgress()
+	outputFile, format := awsr.ResolveOutput(input)
+
+	textView := a.view.Widgets.LogEvent.ViewLog
+	textView.Clear()
+	fmt.Fprintf(textView, "%s log events to %s (%s)\n\n", title, tview.Escape(outputFile), format)
+
+	percent := progress.Percent(input)
+	if a.exportCancel == nil && progress.NextToken == nil && progress.Pages > 0 {
+		percent = 100
+	}
+	filled := int(percent / 100 * progressBarWidth)
+	bar := fmt.Sprintf("[%s%s] %3.0f%%",
+		strings.Repeat("#", filled),
+		strings.Repeat("-", progressBarWidth-filled),
+		percent)
+	fmt.Fprintf(textView, "%s\n\n", tview.Escape(bar))
+
+	fmt.Fprintf(textView, "Pages:  %d\n", progress.Pages)
+	fmt.Fprintf(textView, "Events: %d\n", progress.Events)
+	fmt.Fprintf(textView, "Bytes:  %s\n", formatBytes(float64(progress.Bytes)))
+	fmt.Fprintf(textView, "Range:  %s ~ %s\n",
+		input.StartTime.Format(awsr.TimestampLayout),
+		input.EndTime.Format(awsr.TimestampLayout))
+	if !progress.Timestamp.IsZero() {
+		fmt.Fprintf(textView, "At:     %s\n", progress.Timestamp.Format(awsr.TimestampLayout))
+	}
+
+	switch {
+	case a.exportCancel != nil:
+		fmt.Fprintf(textView, "\nPress '%s' to stop.\n", CancelButtonLabel)
+	case a.state.LogEvent.CanResumeExport():
+		fmt.Fprintf(textView, "\nStopped after %d pages; the remaining pages were not saved.\n", progress.Pages)
+		fmt.Fprintf(textView, "Press '%s' again to resume, appending to %s.\n", SaveButtonLabel, tview.Escape(outputFile))
+	}
+}
+
+// setExportDefaultsToGui shows the configured output file and format in the export widgets.
+func (a *App) setExportDefaultsToGui(cfg *config.Config) {
+	w := a.view.Widgets.LogEvent
+	w.OutputFile.SetText(cfg.Export.File)
+	if i := slices.Index(view.OutputFormats, cfg.Export.Format); i >= 0 {
+		w.OutputFormat.SetCurrentOption(i)
+	}
+}
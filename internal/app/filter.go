@@ -0,0 +1,100 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rivo/tview"
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// filterHint explains the filter pattern syntax while the input is empty.
+const filterHint = `Filter: all events. Use terms, "phrases", ?or, -not, %regex%, { $.field = value } or [field, ..., field = value]`
+
+// ValidateFilterPattern checks the filter pattern being typed and shows the result
+// below the input. Invalid patterns are colored and their error is shown with its position.
+func (a *App) ValidateFilterPattern(text string) (*awsr.FilterPattern, bool) {
+	input := a.view.Widgets.LogEvent.FilterPatern
+	pattern, err := awsr.ParseFilterPattern(text)
+	if err != nil {
+		input.SetFieldTextColor(view.FilterInvalidColor)
+		a.setFilterStatus(filterErrorText(text, err))
+		return nil, false
+	}
+	input.SetFieldTextColor(view.FilterValidColor)
+
+	switch {
+	case pattern.Kind == awsr.FilterAll && text == a.state.LogEvent.GetFilterPatern():
+		a.setFilterStatus(filterHint)
+	case text == a.state.LogEvent.GetFilterPatern():
+		a.setFilterStatus(fmt.Sprintf("[green]Filter: valid %s pattern[-], applied (%s tests it on the loaded events)",
+			awsr.FilterPatternKindNames[pattern.Kind], a.keyHint(view.ActionTestFilter)))
+	default:
+		a.setFilterStatus(fmt.Sprintf("[green]Filter: valid %s pattern[-], %s applies it, %s tests it on the loaded events",
+			awsr.FilterPatternKindNames[pattern.Kind], a.keyHint(view.ActionSelect), a.keyHint(view.ActionTestFilter)))
+	}
+	return pattern, true
+}
+
+// ApplyFilterPattern reloads the log events with the typed filter pattern, if it is valid.
+func (a *App) ApplyFilterPattern() {
+	text := a.view.Widgets.LogEvent.FilterPatern.GetText()
+	if _, ok := a.ValidateFilterPattern(text); !ok {
+		return
+	}
+	a.state.LogEvent.SetFilterPatern(text)
+	a.ValidateFilterPattern(text)
+	a.LoadLogEvents()
+}
+
+// TestFilterPattern counts how many of the loaded log events match the typed filter pattern,
+// without fetching anything from AWS.
+func (a *App) TestFilterPattern() {
+	text := a.view.Widgets.LogEvent.FilterPatern.GetText()
+	pattern, ok := a.ValidateFilterPattern(text)
+	if !ok {
+		return
+	}
+
+	events := a.state.LogEvent.GetEvents()
+	matched := 0
+	for _, event := range events {
+		if pattern.Match(aws.ToString(event.Message)) {
+			matched++
+		}
+	}
+	color := "green"
+	if matched == 0 {
+		color = "yellow"
+	}
+	a.setFilterStatus(fmt.Sprintf("[%s]Filter: matches %d of %d loaded events[-] (%s applies it)", color, matched, len(events), a.keyHint(view.ActionSelect)))
+}
+
+// filterErrorText formats a syntax error of the filter pattern,
+// marking the position of the error in the pattern.
+func filterErrorText(text string, err error) string {
+	var perr *awsr.FilterPatternError
+	if !errors.As(err, &perr) {
+		return fmt.Sprintf("[red]Filter error: %s[-]", tview.Escape(err.Error()))
+	}
+
+	runes := []rune(text)
+	at := min(perr.Column-1, len(runes))
+	marked := tview.Escape(string(runes[:at])) + "[black:red]"
+	if at < len(runes) {
+		marked += tview.Escape(string(runes[at])) + "[-:-]" + tview.Escape(string(runes[at+1:]))
+	} else {
+		marked += " [-:-]"
+	}
+	return fmt.Sprintf("[red]Filter error at column %d: %s[-]  %s", perr.Column, tview.Escape(perr.Message), marked)
+}
+
+// setFilterStatus replaces the text below the filter pattern input.
+func (a *App) setFilterStatus(text string) {
+	status := a.view.Widgets.LogEvent.FilterStatus
+	status.Clear()
+	fmt.Fprint(status, text)
+}
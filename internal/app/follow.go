@@ -0,0 +1,138 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/state"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// followInterval is the delay between two polls while following log events.
+const followInterval = 2 * time.Second
+
+// ToggleFollow pauses following if it is running and resumes it otherwise.
+func (a *App) ToggleFollow() {
+	if a.state.LogEvent.IsFollowing() {
+		a.StopFollow()
+	} else {
+		a.StartFollow()
+	}
+}
+
+// StartFollow starts polling for log events newer than the last seen one,
+// like `tail -f`, and appends them to the log viewer.
+func (a *App) StartFollow() {
+	a.StopFollow()
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.followCancel = cancel
+	a.state.LogEvent.SetFollowing(true)
+	a.updateLogEventStatus()
+	a.updateEventCount()
+
+	go func() {
+		ticker := time.NewTicker(followInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.pollLogEvents(ctx)
+			}
+		}
+	}()
+}
+
+// StopFollow stops polling for new log events.
+func (a *App) StopFollow() {
+	if a.followCancel != nil {
+		a.followCancel()
+		a.followCancel = nil
+	}
+	a.state.LogEvent.SetFollowing(false)
+	a.updateLogEventStatus()
+}
+
+// pollLogEvents fetches events since the last seen one and appends the new ones.
+func (a *App) pollLogEvents(ctx context.Context) {
+	input := &awsr.LogEventInput{
+		Ctx: ctx,
+	}
+	generation, err := a.state.LogEvent.BeforeFollow(input)
+	if err != nil {
+		a.followFailed(err)
+		return
+	}
+	inputs := a.state.LogEvent.GroupInputs(input, state.Home)
+	// the overlap with the previous poll may fill a whole page with events already seen,
+	// so all pages are read for the follow position to advance
+	outputs, errs := awsr.GetAllLogEventsOfGroups(a.backend(), inputs)
+	if ctx.Err() != nil {
+		return
+	}
+	err, failed := groupErrors(errs)
+	if failed == len(inputs) {
+		a.followFailed(err)
+		return
+	}
+	if err != nil {
+		// the other log groups are still followed, the failed ones are retried by the next poll
+		log.Printf("unable to follow %d of %d log groups: %v", failed, len(inputs), err)
+	}
+
+	dropped := a.state.LogEvent.GetDroppedEvents()
+	events, groups := a.state.LogEvent.AfterFollow(generation, inputs, outputs)
+	dropped = a.state.LogEvent.GetDroppedEvents() - dropped
+	if len(events) == 0 {
+		return
+	}
+	if !a.state.LogEvent.IsMerged() {
+		groups = nil
+	}
+
+	a.tvApp.QueueUpdateDraw(func() {
+		textView := a.view.Widgets.LogEvent.ViewLog
+		if dropped > 0 {
+			// the oldest events were dropped to make room, render the rest again
+			table := a.view.Widgets.LogEvent.EventTable
+			row, _ := table.GetSelection()
+			atEnd := row == table.GetRowCount()-1
+			a.renderTrimmedLogEvents(dropped)
+			textView.ScrollToEnd()
+			if atEnd {
+				table.Select(table.GetRowCount()-1, 0)
+			}
+			return
+		}
+		writeLogEventsToView(textView, events, groups, a.state.LogEvent.GetMessageMode(), a.escaper())
+		textView.ScrollToEnd()
+		a.updateSearchCount()
+		a.appendEventsToTable(events, groups)
+	})
+}
+
+// followFailed pauses following and reports the error, offering to resume.
+func (a *App) followFailed(err error) {
+	a.tvApp.QueueUpdateDraw(func() {
+		a.StopFollow()
+		a.showError("Unable to follow log events", err, a.StartFollow)
+	})
+}
+
+// updateLogEventStatus shows the current follow state in the status view.
+func (a *App) updateLogEventStatus() {
+	status := a.view.Widgets.LogEvent.Status
+	status.Clear()
+	if a.state.LogEvent.IsFollowing() {
+		fmt.Fprintf(status, "[green]Follow: on[-] (every %s)", followInterval)
+	} else {
+		fmt.Fprintf(status, "Follow: off (%s)", a.keyHint(view.ActionToggleFollow))
+	}
+}
@@ -0,0 +1,166 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// OpenHelp shows the keys of the focused panel, followed by the other panels
+// of the current page, on top of the page.
+func (a *App) OpenHelp() {
+	page, ok := a.frontPage()
+	if !ok || page == view.ErrorPage || page == view.HelpPage {
+		return
+	}
+	a.helpFocus = a.tvApp.GetFocus()
+	a.setHelpToGui(page, a.focusScope(page))
+
+	a.view.Pages.ShowPage(view.PageNames[view.HelpPage])
+	a.tvApp.SetFocus(a.view.Widgets.Help.Text)
+}
+
+// CloseHelp hides the help and returns the focus to the panel it was opened from.
+func (a *App) CloseHelp() {
+	a.view.Pages.HidePage(view.PageNames[view.HelpPage])
+	a.tvApp.SetFocus(a.helpFocus)
+}
+
+// frontPage returns the page shown on top.
+func (a *App) frontPage() (view.Page, bool) {
+	name, _ := a.view.Pages.GetFrontPage()
+	for page, pageName := range view.PageNames {
+		if pageName == name {
+			return page, true
+		}
+	}
+	return 0, false
+}
+
+// focusScope returns the key scope of the focused widget.
+// Widgets without their own scope, like an open dropdown list, use the first scope of the page.
+func (a *App) focusScope(page view.Page) view.Scope {
+	w := a.view.Widgets
+	focus := a.tvApp.GetFocus()
+	switch focus {
+	case w.LogGroup.Table:
+		return view.ScopeLogGroup
+	case w.LogStream.Table:
+		return view.ScopeLogStream
+	case w.LogEvent.ViewLog, w.LogEvent.EventTable:
+		return view.ScopeLogView
+	case w.LogEvent.EventDetail:
+		return view.ScopeEventDetail
+	case w.LogEvent.FilterPatern:
+		return view.ScopeFilterInput
+	case w.LogEvent.Search:
+		return view.ScopeSearchInput
+	case w.Help.Text:
+		return view.ScopeHelp
+	}
+	switch focus.(type) {
+	case *tview.InputField, *tview.TextArea:
+		return view.ScopeInput
+	}
+	if scopes := view.PageScopes[page]; len(scopes) > 0 {
+		return scopes[0]
+	}
+	return view.ScopeHelp
+}
+
+// setHelpToGui fills the help with a section per scope, the focused scope first.
+func (a *App) setHelpToGui(page view.Page, focused view.Scope) {
+	scopes := []view.Scope{focused}
+	for _, s := range view.PageScopes[page] {
+		if s != focused {
+			scopes = append(scopes, s)
+		}
+	}
+
+	width := 0
+	sections := make([][]view.Binding, len(scopes))
+	for i, s := range scopes {
+		sections[i] = a.keys.Bindings(s)
+		for _, b := range sections[i] {
+			width = max(width, len(strings.Join(b.Keys, ", ")))
+		}
+	}
+
+	text := a.view.Widgets.Help.Text
+	text.Clear()
+	for i, s := range scopes {
+		title := scopeTitle(s)
+		if i == 0 {
+			title += " (focused)"
+		}
+		fmt.Fprintf(text, "[::bu]%s[::-]\n", title)
+		for _, b := range sections[i] {
+			keys := strings.Join(b.Keys, ", ")
+			fmt.Fprintf(text, "  [::b]%s[::-]%s  %s\n",
+				tview.Escape(keys),
+				strings.Repeat(" ", width-len(keys)),
+				view.Actions[b.Action].Description)
+		}
+		fmt.Fprintln(text)
+	}
+	text.ScrollToBeginning()
+
+	var closeKeys []string
+	for _, b := range a.keys.Bindings(view.ScopeHelp) {
+		if b.Action == view.ActionBack || b.Action == view.ActionHelp {
+			closeKeys = append(closeKeys, b.Keys...)
+		}
+	}
+	text.SetTitle(fmt.Sprintf("%s (%s to close)", view.WidgetNames[view.HelpView], tview.Escape(strings.Join(closeKeys, ", "))))
+}
+
+// keyHint returns the keys bound to the action for a hint in a text with color tags.
+func (a *App) keyHint(action view.Action) string {
+	return tview.Escape(a.keys.Hint(action))
+}
+
+// setUpKeyHints fills the placeholders that hint at keys with the configured keys.
+func (a *App) setUpKeyHints() {
+	a.view.Widgets.LogEvent.Search.SetPlaceholder(fmt.Sprintf("press %s in the log view, %s for regex",
+		a.keys.Hint(view.ActionSearch), a.keys.Hint(view.ActionToggleRegex)))
+	a.view.Widgets.Bookmark.Name.SetPlaceholder(fmt.Sprintf("press %s to rename the selected bookmark",
+		a.keys.Hint(view.ActionRename)))
+}
+
+// scopeTitle returns the name of a scope for a heading, starting with a capital letter.
+func scopeTitle(s view.Scope) string {
+	name := view.ScopeNames[s]
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// setUpKeybindingHelp opens the help from any panel and configures the keys of the help itself.
+func (a *App) setUpKeybindingHelp() {
+	a.tvApp.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		page, ok := a.frontPage()
+		if !ok || page == view.ErrorPage || page == view.HelpPage {
+			return event
+		}
+		if a.keys.Action(a.focusScope(page), event) == view.ActionHelp {
+			a.OpenHelp()
+			return nil
+		}
+		return event
+	})
+
+	a.view.Widgets.Help.Text.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action := a.keys.Action(view.ScopeHelp, event)
+		switch action {
+		case view.ActionBack, view.ActionHelp:
+			a.CloseHelp()
+			return nil
+		}
+		return navigationEvent(action, event)
+	})
+}
@@ -0,0 +1,249 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// queryPollInterval is the delay between two GetQueryResults calls.
+const queryPollInterval = time.Second
+
+// OpenInsights adds or removes the log group from the query targets
+// and switches to the Logs Insights page.
+func (a *App) OpenInsights(logGroupName string) {
+	a.state.Query.ToggleLogGroup(logGroupName)
+	a.setQueryLogGroupsToGui()
+	a.view.Pages.SwitchToPage(view.PageNames[view.InsightsPage])
+	a.tvApp.SetFocus(a.view.Widgets.Query.Query)
+}
+
+// RunQuery starts a Logs Insights query and polls its results until it finishes.
+// Partial results and statistics are shown while the query is running.
+func (a *App) RunQuery() {
+	a.cancelQuery()
+
+	input := &awsr.QueryInput{}
+	if err := a.state.Query.BeforeStart(input); err != nil {
+		a.setQueryStatus(fmt.Sprintf("[red]%v[-]", err))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.queryCancel = cancel
+	input.Ctx = ctx
+
+	a.view.Widgets.Query.Results.Clear()
+	a.setQueryStatus("Starting query...")
+
+	go func() {
+		output, err := a.backend().StartQuery(input)
+		if err != nil {
+			a.queryFailed(ctx, err)
+			return
+		}
+		a.tvApp.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				// cancelled while starting, before cancelQuery could know the query
+				a.stopQuery(output.QueryId)
+				return
+			}
+			a.state.Query.SetQueryId(output.QueryId)
+		})
+
+		for {
+			res, err := a.backend().GetQueryResults(&awsr.QueryResultsInput{
+				QueryId: output.QueryId,
+				Ctx:     ctx,
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				a.queryFailed(ctx, err)
+				return
+			}
+
+			a.tvApp.QueueUpdateDraw(func() {
+				// a poll queued before the query was cancelled must not overwrite the next one
+				if ctx.Err() != nil {
+					return
+				}
+				a.setQueryResultToGui(res)
+				if res.IsDone() {
+					a.endQuery()
+				}
+			})
+			if res.IsDone() {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(queryPollInterval):
+			}
+		}
+	}()
+}
+
+// StopQuery cancels the running query, both locally and in CloudWatch Logs.
+func (a *App) StopQuery() {
+	if a.cancelQuery() {
+		a.setQueryStatus("[yellow]Cancelled[-]")
+	}
+}
+
+// cancelQuery stops polling and sends StopQuery for the running query.
+// It returns true if a query was running.
+func (a *App) cancelQuery() bool {
+	if a.queryCancel == nil {
+		return false
+	}
+	a.queryCancel()
+	a.queryCancel = nil
+
+	queryId := a.state.Query.GetQueryId()
+	a.state.Query.SetQueryId("")
+	if queryId != "" {
+		a.stopQuery(queryId)
+	}
+	return true
+}
+
+// stopQuery sends StopQuery in the background, so the query does not keep running in CloudWatch Logs.
+// It uses the context of the app, since the context of the query is already cancelled.
+func (a *App) stopQuery(queryId string) {
+	go func() {
+		err := a.backend().StopQuery(&awsr.QueryResultsInput{
+			QueryId: queryId,
+			Ctx:     a.ctx,
+		})
+		if err != nil {
+			log.Printf("unable to stop query, %v", err)
+		}
+	}()
+}
+
+// endQuery forgets the query that finished or failed, there is nothing left to stop.
+// It must be called on the UI thread while the query is the running one.
+func (a *App) endQuery() {
+	// releases the context of the query
+	a.queryCancel()
+	a.queryCancel = nil
+	a.state.Query.SetQueryId("")
+}
+
+// queryFailed reports a query error in the status view and an error modal,
+// unless the query was cancelled in the meantime.
+func (a *App) queryFailed(ctx context.Context, err error) {
+	a.tvApp.QueueUpdateDraw(func() {
+		if ctx.Err() != nil {
+			return
+		}
+		a.endQuery()
+		a.setQueryStatus("[red]Failed[-]")
+		a.showError("Unable to run query", err, a.RunQuery)
+	})
+}
+
+// setQueryStatus replaces the text of the query status view.
+func (a *App) setQueryStatus(text string) {
+	status := a.view.Widgets.Query.Status
+	status.Clear()
+	fmt.Fprint(status, text)
+}
+
+// setQueryLogGroupsToGui lists the log groups the query runs against.
+func (a *App) setQueryLogGroupsToGui() {
+	textView := a.view.Widgets.Query.LogGroups
+	textView.Clear()
+
+	logGroupNames := a.state.Query.GetLogGroupsSelected()
+	if len(logGroupNames) == 0 {
+		fmt.Fprintln(textView, "No log group selected.")
+		fmt.Fprintf(textView, "Press '%s' on a log group to add it.\n", a.keyHint(view.ActionInsights))
+		return
+	}
+	for _, logGroupName := range logGroupNames {
+		fmt.Fprintf(textView, "%s\n", logGroupName)
+	}
+}
+
+// setQueryResultToGui renders query statistics in the status view and
+// the result rows in the result table, one column per returned field.
+func (a *App) setQueryResultToGui(res *awsr.QueryResultsOutput) {
+	a.setQueryStatus(fmt.Sprintf("%s  matched: %.0f  scanned: %.0f records, %s",
+		res.Status,
+		res.RecordsMatched,
+		res.RecordsScanned,
+		formatBytes(res.BytesScanned)))
+
+	var fields []string
+	for _, result := range res.Results {
+		for _, field := range result {
+			name := aws.ToString(field.Field)
+			// @ptr is an internal pointer to the log record, not meant for display
+			if name != "@ptr" && !slices.Contains(fields, name) {
+				fields = append(fields, name)
+			}
+		}
+	}
+
+	table := a.view.Widgets.Query.Results
+	table.Clear()
+
+	row := 0
+	for i, field := range fields {
+		table.SetCell(row, i, &tview.TableCell{
+			Text:            field,
+			NotSelectable:   true,
+			Align:           tview.AlignLeft,
+			Color:           tcell.ColorWhite,
+			BackgroundColor: tcell.ColorDefault,
+			Attributes:      tcell.AttrBold,
+		})
+	}
+	row++
+
+	for _, result := range res.Results {
+		values := make(map[string]string, len(result))
+		for _, field := range result {
+			values[aws.ToString(field.Field)] = aws.ToString(field.Value)
+		}
+
+		for i, field := range fields {
+			expansion := 1
+			if field == "@message" {
+				expansion = 5
+			}
+			value := tview.Escape(strings.ReplaceAll(values[field], "\n", " "))
+			table.SetCell(row, i, tview.NewTableCell(value).
+				SetTextColor(tcell.ColorLightGreen).
+				SetMaxWidth(1).
+				SetExpansion(expansion))
+		}
+		row++
+	}
+}
+
+// formatBytes formats a byte count with a binary unit suffix.
+func formatBytes(b float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	i := 0
+	for b >= 1024 && i < len(units)-1 {
+		b /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", b, units[i])
+}
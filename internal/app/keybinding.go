@@ -3,24 +3,59 @@ package app
 
 import (
 	"fmt"
-	"log"
 	"reflect"
-	"slices"
 	"strconv"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
 	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/state"
 	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
 )
 
 // setUpKeyBindings initializes keyboard shortcuts for all UI components.
-// It configures navigation, selection, and action key bindings.
+// Keys are looked up in the keymap, so every handler follows the configured keys.
 func (a *App) setUpKeyBindings() {
 	a.setUpKeybindingLogGroup()
 	a.setUpKeybindingLogStream()
 	a.setUpKeybindingLogEvent()
+	a.setUpKeybindingQuery()
+	a.setUpKeybindingProfile()
+	a.setUpKeybindingBookmark()
+	a.setUpKeybindingHelp()
+}
+
+// navigationEvent translates the up, down and select actions to the arrow and
+// Enter keys tables and lists handle themselves, so that remapped keys work too.
+func navigationEvent(action view.Action, event *tcell.EventKey) *tcell.EventKey {
+	switch action {
+	case view.ActionUp:
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	case view.ActionDown:
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case view.ActionSelect:
+		return tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	}
+	return event
+}
+
+// stepDropDown handles the up and down actions of a dropdown.
+// A closed dropdown moves to the previous or next option and opens its list,
+// an open one moves the highlight of its list.
+func stepDropDown(dd *tview.DropDown, action view.Action) *tcell.EventKey {
+	delta, key := 1, tcell.KeyDown
+	if action == view.ActionUp {
+		delta, key = -1, tcell.KeyUp
+	}
+	if !dd.IsOpen() {
+		idx, _ := dd.GetCurrentOption()
+		if i := idx + delta; i >= 0 && i < dd.GetOptionCount() {
+			dd.SetCurrentOption(i)
+		}
+		key = tcell.KeyDown
+	}
+	return tcell.NewEventKey(key, 0, tcell.ModNone)
 }
 
 // setUpKeybindingLogGroup configures keyboard shortcuts for the log group interface.
@@ -33,27 +68,58 @@ func (a *App) setUpKeybindingLogGroup() {
 	// log group table
 	lgTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		row, _ := lgTable.GetSelection()
-		max := lgTable.GetRowCount()
-
-		switch event.Rune() {
-		case 'k', 'j':
-			// vim-style up/down navigation
-			// wait for table to be updated
-			if max > 0 {
-				lgTable.Select(row%max, 0)
-			}
-		case '/':
-			a.tvApp.SetFocus(lgSearch)
-		}
 
-		if event.Key() == tcell.KeyTab {
+		action := a.keys.Action(view.ScopeLogGroup, event)
+		switch action {
+		case view.ActionSearch:
+			a.tvApp.SetFocus(lgSearch)
+			return nil
+		case view.ActionInsights:
+			// add/remove the log group to the Logs Insights query
+			groupName := lgTable.GetCell(row, 1).Text
+			if groupName != "" && groupName != NextPage && groupName != PrevPage {
+				a.OpenInsights(groupName)
+			}
+			return nil
+		case view.ActionToggleSelect:
+			// mark/unmark the log group to view the events of several log groups at once
+			groupName := lgTable.GetCell(row, 1).Text
+			if groupName != "" && groupName != NextPage && groupName != PrevPage {
+				selectedMark := ""
+				if a.state.LogGroup.ToggleSelected(groupName) {
+					selectedMark = "x"
+				}
+				lgTable.GetCell(row, 0).SetText(selectedMark)
+				a.setLogGroupTitle()
+			}
+			return nil
+		case view.ActionProfile:
+			// switch AWS profile and region
+			a.OpenProfilePicker()
+			return nil
+		case view.ActionBookmarks:
+			// open a saved log event view
+			a.OpenBookmarks(view.LogGroupAndStreamPage)
+			return nil
+		case view.ActionReload:
+			a.LoadLogGroups(state.Home)
+			return nil
+		case view.ActionFocusNext:
 			a.tvApp.SetFocus(lsTable)
+			return nil
 		}
-
-		return event
+		return navigationEvent(action, event)
 	})
 	lgTable.SetSelectedFunc(func(row, _ int) {
-		cell := lgTable.GetCell(row, 0)
+		if groupNames := a.state.LogGroup.GetSelected(); len(groupNames) > 0 {
+			// merge the events of all streams of the marked log groups
+			a.state.LogEvent.SetLogGroupsSelected(groupNames)
+			a.state.LogEvent.SetLogStreamsSelected([]string{})
+			a.openLogEvents()
+			return
+		}
+
+		cell := lgTable.GetCell(row, 1)
 		groupName := cell.Text
 
 		a.state.LogEvent.SetLogGroupSelected(groupName)
@@ -64,7 +130,7 @@ func (a *App) setUpKeybindingLogGroup() {
 
 	// Search form
 	lgTable.SetSelectionChangedFunc(func(row, _ int) {
-		cell := lgTable.GetCell(row, 0)
+		cell := lgTable.GetCell(row, 1)
 		switch cell.Text {
 		case NextPage:
 			a.LoadLogGroups(state.Next)
@@ -78,10 +144,13 @@ func (a *App) setUpKeybindingLogGroup() {
 		}
 	})
 	lgSearch.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyEsc {
+		action := a.keys.Action(view.ScopeInput, event)
+		switch action {
+		case view.ActionBack, view.ActionFocusNext:
 			a.tvApp.SetFocus(lgTable)
+			return nil
 		}
-		return event
+		return navigationEvent(action, event)
 	})
 	lgSearch.SetChangedFunc(func(pattern string) {
 		a.state.LogGroup.SetFilterPattern(pattern)
@@ -96,42 +165,58 @@ func (a *App) setUpKeybindingLogStream() {
 	lgTable := a.view.Widgets.LogGroup.Table
 
 	lsTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		max := lsTable.GetRowCount()
-		row, _ := lsTable.GetSelection()
-		switch event.Rune() {
-		case 'k', 'j':
-			// vim-style up/down navigation
-			// wait for table to be updated
-			if max > 0 {
-			        lsTable.Select(row%max, 0)
-			}
-
-		}
-
-		if event.Key() == tcell.KeyTab {
+		action := a.keys.Action(view.ScopeLogStream, event)
+		switch action {
+		case view.ActionReload:
+			a.LoadLogStreams(state.Home)
+			return nil
+		case view.ActionFocusNext:
 			a.tvApp.SetFocus(lgTable)
+			return nil
+		case view.ActionToggleSelect:
+			// mark/unmark the log stream without leaving the page
+			row, _ := lsTable.GetSelection()
+			if name, ok := logStreamAt(lsTable, row); ok {
+				a.state.LogStream.ToggleSelected(name)
+				a.refreshLogStreamMarks()
+			}
+			return nil
+		case view.ActionSelectAll:
+			a.state.LogStream.SelectAll(a.pageLogStreams())
+			a.refreshLogStreamMarks()
+			return nil
+		case view.ActionClearSelection:
+			a.state.LogStream.ClearSelected()
+			a.refreshLogStreamMarks()
+			return nil
+		case view.ActionInvertSelection:
+			a.state.LogStream.InvertSelected(a.pageLogStreams())
+			a.refreshLogStreamMarks()
+			return nil
 		}
-
-		return event
+		return navigationEvent(action, event)
 	})
 
-	// when table is selected (enter key pressed)
+	// when table is selected (enter key pressed):
+	// open the marked log streams, or the one under the cursor if none is marked
 	lsTable.SetSelectedFunc(func(row, col int) {
-		cell := lsTable.GetCell(row, 1)
-		logStreamName := cell.Text
-		if logStreamName == "All Log Streams" {
+		logStreamName, ok := logStreamAt(lsTable, row)
+		selected := a.state.LogStream.GetSelected()
+		switch {
+		case lsTable.GetCell(row, 1).Text == AllLogStreams:
 			a.state.LogEvent.SetLogStreamsSelected([]string{})
-		} else {
-			a.state.LogEvent.SetLogStreamsSelected(
-				slices.Compact(
-					append(a.state.LogEvent.GetLogStreamsSelected(),
-						logStreamName)))
+		case len(selected) > 0:
+			if err := awsr.CheckLogStreamNames(selected); err != nil {
+				a.showError("Unable to open the marked log streams", err, nil)
+				return
+			}
+			a.state.LogEvent.SetLogStreamsSelected(selected)
+		case ok:
+			a.state.LogEvent.SetLogStreamsSelected([]string{logStreamName})
+		default:
+			return
 		}
-		a.state.LogEvent.SetDefaultTime()
-		a.setDefaultDropDownLogEvents()
-		a.LoadLogEvents()
-		a.view.Pages.SwitchToPage(view.PageNames[view.LogEventPage])
-		a.tvApp.SetFocus(a.view.Widgets.LogEvent.StartYear)
+		a.openLogEvents()
 	})
 
 	// when table is focused
@@ -147,6 +232,55 @@ func (a *App) setUpKeybindingLogStream() {
 
 }
 
+// openLogEvents loads the log events of the selected log groups and streams
+// over the default time range and switches to the log event page.
+func (a *App) openLogEvents() {
+	a.state.LogEvent.SetDefaultTime()
+	a.setDefaultDropDownLogEvents()
+	a.LoadLogEvents()
+	a.view.Pages.SwitchToPage(view.PageNames[view.LogEventPage])
+	a.tvApp.SetFocus(a.view.Widgets.LogEvent.Preset)
+}
+
+// BackToLogStreams leaves the log event page for the log group and stream page.
+func (a *App) BackToLogStreams() {
+	a.StopFollow()
+	a.view.Pages.SwitchToPage(view.PageNames[view.LogGroupAndStreamPage])
+	a.tvApp.SetFocus(a.view.Widgets.LogStream.Table)
+}
+
+// formAction handles the actions shared by the widgets of the log event form:
+// moving the focus to next, going back, saving and selecting.
+// It returns the event to pass on to the widget, nil if the action was handled.
+func (a *App) formAction(action view.Action, next tview.Primitive, event *tcell.EventKey) *tcell.EventKey {
+	switch action {
+	case view.ActionFocusNext:
+		a.tvApp.SetFocus(next)
+		return nil
+	case view.ActionBack:
+		a.BackToLogStreams()
+		return nil
+	case view.ActionSave:
+		a.SaveLogEvents()
+		return nil
+	}
+	return navigationEvent(action, event)
+}
+
+// inputAction handles the actions shared by the text inputs of the log event form:
+// moving the focus to next, going back and submitting. It returns nil if the action was handled.
+func (a *App) inputAction(action view.Action, next tview.Primitive, event *tcell.EventKey) *tcell.EventKey {
+	switch action {
+	case view.ActionFocusNext:
+		a.tvApp.SetFocus(next)
+		return nil
+	case view.ActionBack:
+		a.BackToLogStreams()
+		return nil
+	}
+	return navigationEvent(action, event)
+}
+
 // setUpKeybindingLogEvent configures keyboard shortcuts for the log event viewer.
 // It manages date/time selection, filtering, and navigation between form elements.
 func (a *App) setUpKeybindingLogEvent() {
@@ -163,6 +297,8 @@ func (a *App) setUpKeybindingLogEvent() {
 		a.view.Widgets.LogEvent.EndMinute,
 	}
 
+	a.setUpKeybindingTimeRange()
+
 	for i, dd := range dds {
 		currentD := dd
 		currentL := currentD.GetLabel()
@@ -175,94 +311,83 @@ func (a *App) setUpKeybindingLogEvent() {
 		}
 		currentD.
 			SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-				switch event.Rune() {
-				case 'k':
-					// up
-					max := currentD.GetOptionCount()
-					idx, _ := currentD.GetCurrentOption()
-					if idx >= 1 {
-						currentD.SetCurrentOption((idx - 1) % max)
-					}
-
-				case 'j':
-					// down
-					max := currentD.GetOptionCount()
-					idx, _ := currentD.GetCurrentOption()
-					if idx < max-1 {
-						currentD.SetCurrentOption((idx + 1) % max)
-					}
-				}
-
-				if event.Key() == tcell.KeyEsc {
-					a.view.Pages.SwitchToPage(view.PageNames[view.LogGroupAndStreamPage])
-					a.tvApp.SetFocus(a.view.Widgets.LogStream.Table)
-				} else if event.Key() == tcell.KeyTab {
-					a.tvApp.SetFocus(nextWidget)
-				} else if event.Key() == tcell.KeyEnter {
+				action := a.keys.Action(view.ScopeForm, event)
+				switch action {
+				case view.ActionUp, view.ActionDown:
+					return stepDropDown(currentD, action)
+				case view.ActionSelect:
 					if currentD.IsOpen() {
 						if i, _ := currentD.GetCurrentOption(); i != -1 {
 							a.LoadLogEvents()
 						}
 					}
 				}
-				return event
+				return a.formAction(action, nextWidget, event)
 			})
 
 		switch currentL {
 		case view.WidgetNames[view.StartMonthDropDown]:
 			currentD.SetSelectedFunc(func(text string, index int) {
-				a.state.LogEvent.SetTime(currentL, text)
-
-				a.view.Widgets.LogEvent.StartDay.
-					SetOptions(getDaysByMonth(text), nil).
-					SetSelectedFunc(func(text string, index int) {
-						a.state.LogEvent.SetTime(view.WidgetNames[view.StartDayDropDown], text)
-					})
+				a.setTime(currentL, text)
+				a.setDayOptions(a.view.Widgets.LogEvent.StartDay, view.StartDayDropDown, text)
 			})
 		case view.WidgetNames[view.EndMonthDropDown]:
 			currentD.SetSelectedFunc(func(text string, index int) {
-				a.state.LogEvent.SetTime(currentL, text)
-
-				a.view.Widgets.LogEvent.EndDay.
-					SetOptions(getDaysByMonth(text), nil).
-					SetSelectedFunc(func(text string, index int) {
-						a.state.LogEvent.SetTime(view.WidgetNames[view.EndDayDropDown], text)
-					})
+				a.setTime(currentL, text)
+				a.setDayOptions(a.view.Widgets.LogEvent.EndDay, view.EndDayDropDown, text)
 			})
 		default:
 			currentD.SetSelectedFunc(func(text string, index int) {
-				a.state.LogEvent.SetTime(currentL, text)
+				a.setTime(currentL, text)
 			})
 		}
 	}
 
-	a.view.Widgets.LogEvent.FilterPatern.
+	filter := a.view.Widgets.LogEvent.FilterPatern
+	filter.
 		SetDoneFunc(func(key tcell.Key) {
-			if key == tcell.KeyTab {
-				a.tvApp.SetFocus(a.view.Widgets.LogEvent.OutputFile)
+			if key == tcell.KeyEnter {
+				a.ApplyFilterPattern()
 			}
 		}).
 		SetChangedFunc(func(text string) {
-			a.state.LogEvent.SetFilterPatern(text)
-			a.LoadLogEvents()
-		})
-
-	a.view.Widgets.LogEvent.OutputFile.
-		SetDoneFunc(func(key tcell.Key) {
-			if key == tcell.KeyTab {
-				a.tvApp.SetFocus(a.view.Widgets.LogEvent.SaveEventLog)
-			}
+			a.ValidateFilterPattern(text)
 		}).
-		SetChangedFunc(func(text string) {
-			a.state.LogEvent.SetOutputFile(text)
+		SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			action := a.keys.Action(view.ScopeFilterInput, event)
+			if action == view.ActionTestFilter {
+				a.TestFilterPattern()
+				return nil
+			}
+			return a.inputAction(action, a.view.Widgets.LogEvent.OutputFile, event)
 		})
+	a.ValidateFilterPattern(filter.GetText())
+
+	outputFile := a.view.Widgets.LogEvent.OutputFile
+	outputFile.SetChangedFunc(func(text string) {
+		a.state.LogEvent.SetOutputFile(text)
+	})
+	outputFile.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		return a.inputAction(a.keys.Action(view.ScopeInput, event), a.view.Widgets.LogEvent.OutputFormat, event)
+	})
+
+	formatDD := a.view.Widgets.LogEvent.OutputFormat
+	formatDD.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action := a.keys.Action(view.ScopeForm, event)
+		if action == view.ActionUp || action == view.ActionDown {
+			return stepDropDown(formatDD, action)
+		}
+		return a.formAction(action, a.view.Widgets.LogEvent.SaveEventLog, event)
+	})
+	formatDD.SetSelectedFunc(func(text string, index int) {
+		if err := a.state.LogEvent.SetOutputFormat(text); err != nil {
+			a.showError("Invalid output format", err, nil)
+		}
+	})
 
 	saveButton := a.view.Widgets.LogEvent.SaveEventLog
 	saveButton.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyTab {
-			a.tvApp.SetFocus(a.view.Widgets.LogEvent.Back)
-		}
-		return event
+		return a.formAction(a.keys.Action(view.ScopeForm, event), a.view.Widgets.LogEvent.Back, event)
 	})
 	saveButton.SetSelectedFunc(func() {
 		a.SaveLogEvents()
@@ -270,24 +395,390 @@ func (a *App) setUpKeybindingLogEvent() {
 
 	backButton := a.view.Widgets.LogEvent.Back
 	backButton.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyTab {
-			a.tvApp.SetFocus(a.view.Widgets.LogEvent.ViewLog)
-		}
-		return event
-	})
-	backButton.SetSelectedFunc(func() {
-		a.view.Pages.SwitchToPage(view.PageNames[view.LogGroupAndStreamPage])
-		a.tvApp.SetFocus(a.view.Widgets.LogStream.Table)
+		return a.formAction(a.keys.Action(view.ScopeForm, event), a.logEventsView(), event)
 	})
+	backButton.SetSelectedFunc(a.BackToLogStreams)
 
 	viewLog := a.view.Widgets.LogEvent.ViewLog
 	viewLog.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyTab {
-			a.tvApp.SetFocus(a.view.Widgets.LogEvent.StartYear)
+		action := a.keys.Action(view.ScopeLogView, event)
+		if a.logViewAction(action) {
+			return nil
+		}
+		switch action {
+		case view.ActionDown:
+			a.scrollDownLogEvents()
+		case view.ActionFocusNext:
+			a.tvApp.SetFocus(a.view.Widgets.LogEvent.Preset)
+			return nil
 		}
-		return event
+		return navigationEvent(action, event)
 	})
 	viewLog.SetScrollable(true)
+
+	a.setUpKeybindingEventTable()
+	a.setUpKeybindingSearch()
+}
+
+// logViewAction runs the actions shared by the text view and the table of the log viewer.
+// It returns false if the action is not one of them.
+func (a *App) logViewAction(action view.Action) bool {
+	switch action {
+	case view.ActionToggleFollow:
+		// pause/resume following new events
+		a.ToggleFollow()
+	case view.ActionMore:
+		// load the next page of events
+		a.LoadMoreLogEvents()
+	case view.ActionMessageMode:
+		// switch between raw, compact, pretty and colored JSON messages
+		a.ToggleMessageMode()
+	case view.ActionToggleView:
+		// switch between the text view and the table
+		a.ToggleEventView()
+	case view.ActionToggleUTC:
+		// switch between local time and UTC
+		a.ToggleUTC()
+	case view.ActionSearch:
+		// search in the loaded events
+		a.OpenSearch()
+	case view.ActionNextMatch:
+		// jump to the next search match
+		a.NextMatch(1)
+	case view.ActionPrevMatch:
+		// jump to the previous search match
+		a.NextMatch(-1)
+	case view.ActionBookmark:
+		// bookmark this view
+		a.AddBookmark()
+	case view.ActionConsoleLink:
+		// copy the console link of the selected event or of the view
+		a.CopyConsoleLink()
+	case view.ActionVisual:
+		// select a range of events in the table
+		a.ToggleVisual()
+	case view.ActionYank:
+		a.Yank(awsr.FormatRaw)
+	case view.ActionYankJSON:
+		a.Yank(awsr.FormatJSONL)
+	case view.ActionReload:
+		a.LoadLogEvents()
+	case view.ActionSave:
+		a.SaveLogEvents()
+	case view.ActionBack:
+		a.BackToLogStreams()
+	default:
+		return false
+	}
+	return true
+}
+
+// setUpKeybindingSearch configures keyboard shortcuts for the search input of the log viewer.
+func (a *App) setUpKeybindingSearch() {
+	search := a.view.Widgets.LogEvent.Search
+	viewLog := a.view.Widgets.LogEvent.ViewLog
+
+	search.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action := a.keys.Action(view.ScopeSearchInput, event)
+		switch action {
+		case view.ActionToggleRegex:
+			// switch between plain and regular expression search
+			if search.GetLabel() == view.RegexSearchLabel {
+				search.SetLabel(view.SearchLabel)
+			} else {
+				search.SetLabel(view.RegexSearchLabel)
+			}
+			a.Search(search.GetText(), search.GetLabel() == view.RegexSearchLabel)
+			return nil
+		case view.ActionBack:
+			search.SetText("")
+			a.Search("", false)
+			a.tvApp.SetFocus(viewLog)
+			return nil
+		case view.ActionFocusNext:
+			a.tvApp.SetFocus(viewLog)
+			return nil
+		}
+		return navigationEvent(action, event)
+	})
+	// highlight the matches once typing pauses
+	search.SetChangedFunc(func(text string) {
+		a.SearchLater(text, search.GetLabel() == view.RegexSearchLabel)
+	})
+	search.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			if a.Search(search.GetText(), search.GetLabel() == view.RegexSearchLabel) {
+				a.tvApp.SetFocus(viewLog)
+			}
+		}
+	})
+}
+
+// setUpKeybindingEventTable configures keyboard shortcuts for the event table and its detail pane.
+func (a *App) setUpKeybindingEventTable() {
+	table := a.view.Widgets.LogEvent.EventTable
+	detail := a.view.Widgets.LogEvent.EventDetail
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action := a.keys.Action(view.ScopeLogView, event)
+		if _, visual := a.state.LogEvent.GetVisual(); visual && action == view.ActionBack {
+			// Esc cancels the visual selection before leaving the log viewer
+			a.StopVisual()
+			return nil
+		}
+		if a.logViewAction(action) {
+			return nil
+		}
+		switch action {
+		case view.ActionDown:
+			// moving down from the last row loads the next page of events
+			row, _ := table.GetSelection()
+			if row == table.GetRowCount()-1 {
+				a.LoadMoreLogEvents()
+			}
+		case view.ActionFocusNext:
+			a.tvApp.SetFocus(detail)
+			return nil
+		}
+		return navigationEvent(action, event)
+	})
+	table.SetSelectionChangedFunc(func(row, col int) {
+		a.setEventDetailToGui(row)
+		if _, visual := a.state.LogEvent.GetVisual(); visual {
+			a.refreshVisualRows()
+		}
+	})
+
+	detail.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action := a.keys.Action(view.ScopeEventDetail, event)
+		switch action {
+		case view.ActionFocusNext:
+			a.tvApp.SetFocus(a.view.Widgets.LogEvent.Preset)
+			return nil
+		case view.ActionBack:
+			a.tvApp.SetFocus(table)
+			return nil
+		}
+		return navigationEvent(action, event)
+	})
+}
+
+// setUpKeybindingTimeRange configures keyboard shortcuts for the time preset dropdown
+// and the time range expression input of the log event viewer.
+func (a *App) setUpKeybindingTimeRange() {
+	presetDD := a.view.Widgets.LogEvent.Preset
+	timeRange := a.view.Widgets.LogEvent.TimeRange
+
+	presetDD.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action := a.keys.Action(view.ScopeForm, event)
+		if action == view.ActionUp || action == view.ActionDown {
+			return stepDropDown(presetDD, action)
+		}
+		return a.formAction(action, timeRange, event)
+	})
+	presetDD.SetSelectedFunc(func(text string, index int) {
+		if text == view.CustomTimePreset {
+			a.state.LogEvent.SetCustomTime()
+			return
+		}
+		d, err := state.ParsePreset(text)
+		if err != nil {
+			a.showError("Invalid time preset", err, nil)
+			return
+		}
+		if d == a.state.LogEvent.GetRelativeTime() {
+			return
+		}
+		a.state.LogEvent.SetRelativeTime(d)
+		a.setDefaultDropDownLogEvents()
+		a.LoadLogEvents()
+	})
+
+	timeRange.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		return a.inputAction(a.keys.Action(view.ScopeInput, event), a.view.Widgets.LogEvent.StartYear, event)
+	})
+	timeRange.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			tr, err := state.ParseTimeRange(timeRange.GetText(), time.Now())
+			if err != nil {
+				a.showError("Invalid time range", err, nil)
+				return
+			}
+			a.state.LogEvent.SetTimeRange(tr)
+			a.setDefaultDropDownLogEvents()
+			a.LoadLogEvents()
+		}
+	})
+}
+
+// setUpKeybindingQuery configures keyboard shortcuts for the Logs Insights query page.
+// It handles query editing, time range selection, running and cancelling queries.
+func (a *App) setUpKeybindingQuery() {
+	queryArea := a.view.Widgets.Query.Query
+	rangeDD := a.view.Widgets.Query.Range
+	runButton := a.view.Widgets.Query.Run
+	stopButton := a.view.Widgets.Query.Stop
+	backButton := a.view.Widgets.Query.Back
+	results := a.view.Widgets.Query.Results
+
+	back := func() {
+		a.view.Pages.SwitchToPage(view.PageNames[view.LogGroupAndStreamPage])
+		a.tvApp.SetFocus(a.view.Widgets.LogGroup.Table)
+	}
+	// queryAction handles moving the focus to next and going back
+	queryAction := func(action view.Action, next tview.Primitive, event *tcell.EventKey) *tcell.EventKey {
+		switch action {
+		case view.ActionFocusNext:
+			a.tvApp.SetFocus(next)
+			return nil
+		case view.ActionBack:
+			back()
+			return nil
+		}
+		return navigationEvent(action, event)
+	}
+
+	queryArea.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		return queryAction(a.keys.Action(view.ScopeInput, event), rangeDD, event)
+	})
+	queryArea.SetChangedFunc(func() {
+		a.state.Query.SetQueryString(queryArea.GetText())
+	})
+
+	rangeDD.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action := a.keys.Action(view.ScopeQuery, event)
+		if action == view.ActionUp || action == view.ActionDown {
+			return stepDropDown(rangeDD, action)
+		}
+		return queryAction(action, runButton, event)
+	})
+	rangeDD.SetSelectedFunc(func(text string, index int) {
+		if err := a.state.Query.SetTimeRange(text); err != nil {
+			a.setQueryStatus(fmt.Sprintf("[red]%v[-]", err))
+		}
+	})
+
+	runButton.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		return queryAction(a.keys.Action(view.ScopeQuery, event), stopButton, event)
+	})
+	runButton.SetSelectedFunc(func() {
+		a.RunQuery()
+	})
+
+	stopButton.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		return queryAction(a.keys.Action(view.ScopeQuery, event), backButton, event)
+	})
+	stopButton.SetSelectedFunc(func() {
+		a.StopQuery()
+	})
+
+	backButton.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		return queryAction(a.keys.Action(view.ScopeQuery, event), results, event)
+	})
+	backButton.SetSelectedFunc(back)
+
+	results.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		return queryAction(a.keys.Action(view.ScopeQuery, event), queryArea, event)
+	})
+}
+
+// setUpKeybindingProfile configures keyboard shortcuts for the profile and region picker.
+// Enter on a profile moves to the region list, Enter on a region applies the choice.
+func (a *App) setUpKeybindingProfile() {
+	profileList := a.view.Widgets.Profile.Profiles
+	regionList := a.view.Widgets.Profile.Regions
+
+	for _, list := range []*tview.List{profileList, regionList} {
+		list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			action := a.keys.Action(view.ScopeProfile, event)
+			switch action {
+			case view.ActionBack:
+				a.CloseProfilePicker()
+				return nil
+			case view.ActionFocusNext:
+				if profileList.HasFocus() {
+					a.tvApp.SetFocus(regionList)
+				} else {
+					a.tvApp.SetFocus(profileList)
+				}
+				return nil
+			}
+			return navigationEvent(action, event)
+		})
+	}
+
+	profileList.SetSelectedFunc(func(_ int, profile string, _ string, _ rune) {
+		a.state.Profile.SetProfile(profile)
+		a.tvApp.SetFocus(regionList)
+	})
+	regionList.SetSelectedFunc(func(_ int, region string, _ string, _ rune) {
+		if region == ProfileDefaultRegion {
+			region = ""
+		}
+		a.state.Profile.SetRegion(region)
+		a.SwitchProfile()
+	})
+}
+
+// setUpKeybindingBookmark configures keyboard shortcuts for the bookmarks page.
+func (a *App) setUpKeybindingBookmark() {
+	table := a.view.Widgets.Bookmark.Table
+	name := a.view.Widgets.Bookmark.Name
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action := a.keys.Action(view.ScopeBookmark, event)
+		switch action {
+		case view.ActionRename:
+			a.RenameBookmark()
+			return nil
+		case view.ActionDelete:
+			a.DeleteBookmark()
+			return nil
+		case view.ActionBack:
+			a.CloseBookmarks()
+			return nil
+		}
+		return navigationEvent(action, event)
+	})
+	table.SetSelectedFunc(func(_, _ int) {
+		a.OpenSelectedBookmark()
+	})
+
+	name.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action := a.keys.Action(view.ScopeInput, event)
+		switch action {
+		case view.ActionBack, view.ActionFocusNext:
+			a.CancelBookmarkEdit()
+			return nil
+		}
+		return navigationEvent(action, event)
+	})
+	name.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			a.SubmitBookmarkName()
+		}
+	})
+}
+
+// setTime updates a time component of the log event state,
+// showing an error if the selected value is invalid.
+func (a *App) setTime(label string, text string) {
+	if err := a.state.LogEvent.SetTime(label, text); err != nil {
+		a.showError("Invalid time", err, nil)
+	}
+}
+
+// setDayOptions updates the options of a day dropdown to the days of the selected month.
+func (a *App) setDayOptions(dd *tview.DropDown, widget view.Widget, month string) {
+	days, err := getDaysByMonth(month)
+	if err != nil {
+		a.showError("Invalid month", err, nil)
+		return
+	}
+	dd.SetOptions(days, nil).
+		SetSelectedFunc(func(text string, index int) {
+			a.setTime(view.WidgetNames[widget], text)
+		})
 }
 
 // PrintStructFields returns a string slice containing field names and values of a struct.
@@ -309,12 +800,12 @@ func PrintStructFields(s any) []string {
 
 // getDaysByMonth returns a string slice containing all valid days for a given month.
 // It calculates the correct number of days considering the month and year (2024).
-func getDaysByMonth(month string) []string {
+func getDaysByMonth(month string) ([]string, error) {
 	var days []string
 	y := 2024
 	intMonth, err := strconv.Atoi(month)
 	if err != nil {
-		log.Fatalf("unable to convert month to integer, %v", err)
+		return nil, fmt.Errorf("unable to convert month to integer: %w", err)
 	}
 	m := time.Month(intMonth)
 
@@ -326,5 +817,5 @@ func getDaysByMonth(month string) []string {
 		dayStr := strconv.Itoa(d.Day())
 		days = append(days, dayStr)
 	}
-	return days
+	return days, nil
 }
@@ -0,0 +1,112 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rivo/tview"
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/state"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// ProfileDefaultRegion is the region list entry that keeps the region configured for the profile.
+const ProfileDefaultRegion = "(profile default)"
+
+// OpenProfilePicker fills the profile and region lists and shows the picker page.
+func (a *App) OpenProfilePicker() {
+	client, ok := a.backend().(*awsr.Client)
+	if !ok {
+		a.showError("Unable to switch profile", fmt.Errorf("profiles are not available when running with fixtures"), nil)
+		return
+	}
+	profiles, err := awsr.ListProfiles()
+	if err != nil {
+		a.showError("Unable to list profiles", err, nil)
+		return
+	}
+
+	current := client.Options()
+	a.state.Profile.SetProfile(current.Profile)
+	a.state.Profile.SetRegion("")
+
+	profileList := a.view.Widgets.Profile.Profiles
+	profileList.Clear()
+	for i, profile := range profiles {
+		profileList.AddItem(profile, "", 0, nil)
+		if profile == client.ProfileName() {
+			profileList.SetCurrentItem(i)
+		}
+	}
+
+	regionList := a.view.Widgets.Profile.Regions
+	regionList.Clear()
+	regionList.AddItem(ProfileDefaultRegion, "", 0, nil)
+	for _, region := range awsr.Regions {
+		regionList.AddItem(region, "", 0, nil)
+	}
+
+	a.view.Pages.SwitchToPage(view.PageNames[view.ProfilePage])
+	a.tvApp.SetFocus(profileList)
+}
+
+// CloseProfilePicker returns to the log group page.
+func (a *App) CloseProfilePicker() {
+	a.view.Pages.SwitchToPage(view.PageNames[view.LogGroupAndStreamPage])
+	a.tvApp.SetFocus(a.view.Widgets.LogGroup.Table)
+}
+
+// SwitchProfile rebuilds the AWS client for the profile and region chosen in the picker,
+// resets the pagination state and reloads the log groups.
+func (a *App) SwitchProfile() {
+	client, ok := a.backend().(*awsr.Client)
+	if !ok {
+		return
+	}
+	opts := client.Options()
+	opts.Profile, opts.Region = a.state.Profile.Get()
+
+	newClient, err := awsr.NewClient(a.ctx, opts)
+	if err != nil {
+		a.showError("Unable to switch profile", err, a.SwitchProfile)
+		return
+	}
+
+	a.StopFollow()
+	a.cancelQuery()
+	a.CancelSave()
+
+	a.mu.Lock()
+	// drop the log group and stream loads still running with the old client
+	a.loadCancel()
+	a.loadCtx, a.loadCancel = context.WithCancel(a.ctx)
+	a.awsClient = newClient
+	a.mu.Unlock()
+
+	a.state.Reset()
+	a.view.Widgets.LogStream.Table.Clear()
+	a.setQueryLogGroupsToGui()
+	a.updateHeader()
+	a.CloseProfilePicker()
+	a.LoadLogGroups(state.Home)
+}
+
+// updateHeader shows the active profile and region, or the fixture mode, in the header.
+func (a *App) updateHeader() {
+	header := a.view.Widgets.Header.Text
+	header.Clear()
+
+	switch backend := a.backend().(type) {
+	case *awsr.Client:
+		opts := backend.Options()
+		fmt.Fprintf(header, " Profile: [green]%s[-]  Region: [green]%s[-]",
+			tview.Escape(backend.ProfileName()), tview.Escape(opts.Region))
+		if opts.EndpointURL != "" {
+			fmt.Fprintf(header, "  Endpoint: [yellow]%s[-]", tview.Escape(opts.EndpointURL))
+		}
+		fmt.Fprintf(header, "  (%s: switch)", a.keyHint(view.ActionProfile))
+	case *awsr.MemoryBackend:
+		fmt.Fprintf(header, " [yellow]Offline[-] (fixtures)")
+	}
+}
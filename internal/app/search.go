@@ -0,0 +1,137 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// searchDelay is how long typing in the search input has to pause before the matches are highlighted,
+// so that a large log view is not rendered again on every key.
+const searchDelay = 150 * time.Millisecond
+
+// OpenSearch moves the focus to the search input of the log viewer.
+// Search works on the text view, so the table view is left first.
+func (a *App) OpenSearch() {
+	if a.state.LogEvent.IsTableView() {
+		a.ToggleEventView()
+	}
+	a.tvApp.SetFocus(a.view.Widgets.LogEvent.Search)
+}
+
+// Search highlights the matches of the query in the loaded log events
+// and jumps to the first one. It does not fetch anything from AWS.
+// An empty query clears the search. It returns false if the query is invalid.
+// Only the text view is rendered again, the event table keeps its selection.
+func (a *App) Search(query string, regex bool) bool {
+	// a search typed before this one is outdated
+	a.searchGen++
+	if err := a.state.LogEvent.SetSearch(query, regex); err != nil {
+		a.setSearchStatus(fmt.Sprintf("[red]%s[-]", tview.Escape(err.Error())))
+		return false
+	}
+	a.setLogViewToGui()
+	return true
+}
+
+// SearchLater runs Search once typing pauses for searchDelay.
+// A later call, or a call of Search, replaces the pending search.
+func (a *App) SearchLater(query string, regex bool) {
+	if a.searchTimer != nil {
+		a.searchTimer.Stop()
+	}
+	a.searchGen++
+	gen := a.searchGen
+	a.searchTimer = time.AfterFunc(searchDelay, func() {
+		a.tvApp.QueueUpdateDraw(func() {
+			// the timer may have fired while it was replaced
+			if gen == a.searchGen {
+				a.Search(query, regex)
+			}
+		})
+	})
+}
+
+// NextMatch moves the current search match forward, or backward if delta is negative,
+// wrapping around at both ends. The event table switches to the text view showing the matches.
+func (a *App) NextMatch(delta int) {
+	if a.searchHighlighter == nil || a.searchHighlighter.Count() == 0 {
+		return
+	}
+	if a.state.LogEvent.IsTableView() {
+		// the matches are highlighted in the text view only
+		a.ToggleEventView()
+	}
+	count := a.searchHighlighter.Count()
+	i := (a.state.LogEvent.GetSearchMatch() + delta + count) % count
+	a.state.LogEvent.SetSearchMatch(i)
+	a.highlightMatch()
+}
+
+// newEscaper starts highlighting the search matches of a full render of the log viewer.
+// It returns nil, meaning plain escaping, if there is no search.
+func (a *App) newEscaper() view.Escaper {
+	re := a.state.LogEvent.GetSearch()
+	if re == nil {
+		a.searchHighlighter = nil
+		return nil
+	}
+	a.searchHighlighter = view.NewSearchHighlighter(re)
+	return a.searchHighlighter.Escape
+}
+
+// escaper returns the escaper for events appended to the log viewer,
+// continuing the numbering of the search matches already shown.
+func (a *App) escaper() view.Escaper {
+	if a.searchHighlighter == nil {
+		return nil
+	}
+	return a.searchHighlighter.Escape
+}
+
+// highlightMatch highlights the current search match, scrolls to it and updates the counter.
+func (a *App) highlightMatch() {
+	textView := a.view.Widgets.LogEvent.ViewLog
+	if a.searchHighlighter == nil {
+		textView.Highlight()
+		a.setSearchStatus("")
+		return
+	}
+
+	count := a.searchHighlighter.Count()
+	if count == 0 {
+		textView.Highlight()
+		a.setSearchStatus("[yellow]no matches[-]")
+		return
+	}
+
+	i := min(a.state.LogEvent.GetSearchMatch(), count-1)
+	textView.Highlight(view.MatchRegion(i)).ScrollToHighlight()
+	a.setSearchStatus(a.searchPosition(i, count))
+}
+
+// updateSearchCount updates the counter after matches were appended to the log viewer,
+// without moving away from the current match.
+func (a *App) updateSearchCount() {
+	if a.searchHighlighter == nil || a.searchHighlighter.Count() == 0 {
+		// keep showing "no matches" or nothing
+		return
+	}
+	i := min(a.state.LogEvent.GetSearchMatch(), a.searchHighlighter.Count()-1)
+	a.setSearchStatus(a.searchPosition(i, a.searchHighlighter.Count()))
+}
+
+// searchPosition returns the position of the i-th match with the keys moving between matches.
+func (a *App) searchPosition(i int, count int) string {
+	return fmt.Sprintf("%d/%d (%s %s)", i+1, count, a.keyHint(view.ActionNextMatch), a.keyHint(view.ActionPrevMatch))
+}
+
+// setSearchStatus replaces the text of the search counter.
+func (a *App) setSearchStatus(text string) {
+	status := a.view.Widgets.LogEvent.SearchStatus
+	status.Clear()
+	fmt.Fprint(status, text)
+}
@@ -0,0 +1,140 @@
+// Package app provides the main application logic for the CloudWatch Log TUI.
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwlTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// visualKeys returns the keys of the visual selection for its status line.
+func (a *App) visualKeys() string {
+	return fmt.Sprintf("%s/%s extend it, %s yanks the messages, %s yanks JSON Lines, %s cancels",
+		a.keyHint(view.ActionDown), a.keyHint(view.ActionUp), a.keyHint(view.ActionYank),
+		a.keyHint(view.ActionYankJSON), a.keyHint(view.ActionBack))
+}
+
+// ToggleVisual starts a visual selection of log events at the selected row of the event table,
+// or ends the current one. The text view has no row per event, so it switches to the table first.
+func (a *App) ToggleVisual() {
+	if _, ok := a.state.LogEvent.GetVisual(); ok {
+		a.StopVisual()
+		return
+	}
+	if !a.state.LogEvent.IsTableView() {
+		a.ToggleEventView()
+	}
+
+	row, _ := a.view.Widgets.LogEvent.EventTable.GetSelection()
+	if _, ok := a.state.LogEvent.GetEvent(row - 1); !ok {
+		return
+	}
+	a.state.LogEvent.StartVisual(row - 1)
+	a.refreshVisualRows()
+}
+
+// StopVisual ends the visual selection of log events.
+func (a *App) StopVisual() {
+	if _, ok := a.state.LogEvent.GetVisual(); !ok {
+		return
+	}
+	a.state.LogEvent.StopVisual()
+	a.refreshVisualRows()
+	a.setFilterStatus("")
+}
+
+// Yank copies the events of the visual selection, or the selected event of the table,
+// to the clipboard as raw messages or in JSON Lines and ends the visual selection.
+func (a *App) Yank(format awsr.OutputFormat) {
+	if !a.state.LogEvent.IsTableView() {
+		a.setFilterStatus(fmt.Sprintf("[yellow]Yank: press %s to select events first[-]", a.keyHint(view.ActionVisual)))
+		return
+	}
+	first, last := a.visualEvents()
+	var events []cwlTypes.FilteredLogEvent
+	for i := first; i <= last; i++ {
+		if event, ok := a.state.LogEvent.GetEvent(i); ok {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		a.setFilterStatus("[yellow]Yank: no events[-]")
+		return
+	}
+	a.state.LogEvent.StopVisual()
+	a.refreshVisualRows()
+
+	text, err := yankText(events, format)
+	if err == nil {
+		var how string
+		how, err = copyToClipboard(text)
+		if err == nil {
+			a.setFilterStatus(fmt.Sprintf("[green]Yanked %d events (%s) with %s[-]", len(events), format, how))
+			return
+		}
+	}
+	a.setFilterStatus(fmt.Sprintf("[red]Yank: %s[-]", tview.Escape(err.Error())))
+}
+
+// yankText returns the events as raw messages, one per line, or in JSON Lines like a saved file.
+func yankText(events []cwlTypes.FilteredLogEvent, format awsr.OutputFormat) (string, error) {
+	var buf bytes.Buffer
+	if format == awsr.FormatRaw {
+		// unlike a saved file, each message gets a line of its own
+		for _, event := range events {
+			buf.WriteString(strings.TrimSuffix(aws.ToString(event.Message), "\n"))
+			buf.WriteByte('\n')
+		}
+		return buf.String(), nil
+	}
+
+	w, err := awsr.NewEventWriter(&buf, format, false)
+	if err != nil {
+		return "", err
+	}
+	if err := w.Write(events); err != nil {
+		return "", err
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// visualEvents returns the indexes of the first and last event of the visual selection,
+// which is the selected event alone without a visual selection.
+func (a *App) visualEvents() (int, int) {
+	row, _ := a.view.Widgets.LogEvent.EventTable.GetSelection()
+	anchor, ok := a.state.LogEvent.GetVisual()
+	if !ok {
+		return row - 1, row - 1
+	}
+	return min(anchor, row-1), max(anchor, row-1)
+}
+
+// refreshVisualRows shows the rows of the visual selection in reverse video
+// and the size of the selection in the status line.
+func (a *App) refreshVisualRows() {
+	table := a.view.Widgets.LogEvent.EventTable
+	_, visual := a.state.LogEvent.GetVisual()
+	first, last := a.visualEvents()
+	for row := 1; row < table.GetRowCount(); row++ {
+		attributes := tcell.AttrNone
+		if visual && first <= row-1 && row-1 <= last {
+			attributes = tcell.AttrReverse
+		}
+		for col := 0; col < table.GetColumnCount(); col++ {
+			table.GetCell(row, col).SetAttributes(attributes)
+		}
+	}
+	if visual {
+		a.setFilterStatus(fmt.Sprintf("[yellow]-- VISUAL --[-] %d events selected, %s", last-first+1, a.visualKeys()))
+	}
+}
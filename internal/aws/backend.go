@@ -0,0 +1,19 @@
+// Package aws provides AWS CloudWatch Logs client functionality for the TUI application.
+package aws
+
+// LogsBackend is the set of CloudWatch Logs operations the TUI depends on.
+// It is implemented by Client for real AWS access and by MemoryBackend for offline use.
+type LogsBackend interface {
+	GetLogGroups(input *LogGroupInput) (*LogGroupOutput, error)
+	GetLogStreams(input *LogStreamInput) (*LogStreamOutput, error)
+	GetLogEvents(input *LogEventInput) (*LogEventOutput, error)
+	WriteLogEvents(input *LogEventInput) error
+	StartQuery(input *QueryInput) (*QueryOutput, error)
+	GetQueryResults(input *QueryResultsInput) (*QueryResultsOutput, error)
+	StopQuery(input *QueryResultsInput) error
+}
+
+var (
+	_ LogsBackend = (*Client)(nil)
+	_ LogsBackend = (*MemoryBackend)(nil)
+)
@@ -5,6 +5,7 @@ package aws
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"time"
 
@@ -14,24 +15,67 @@ import (
 	cwlTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 )
 
-// MaxItemsInLayout defines the maximum number of items to display per page in the UI layout.
-const MaxItemsInLayout int32 = 50
+// DefaultPageSize is the number of log groups and streams listed per page when no limit is given.
+// It is also the most DescribeLogGroups and DescribeLogStreams return at once.
+const DefaultPageSize int32 = 50
+
+// DefaultEventsInPage is the number of log events fetched per page of the log viewer when no limit is given.
+const DefaultEventsInPage int32 = 1000
+
+// MaxEventsInPage is the most log events FilterLogEvents returns at once.
+const MaxEventsInPage int32 = 10000
+
+// MaxLogStreamNames is the most log streams FilterLogEvents reads the events of at once.
+const MaxLogStreamNames = 100
+
+// CheckLogStreamNames returns an error if there are more log streams than FilterLogEvents accepts.
+func CheckLogStreamNames(names []string) error {
+	if len(names) > MaxLogStreamNames {
+		return fmt.Errorf("at most %d log streams can be read together, %d are selected", MaxLogStreamNames, len(names))
+	}
+	return nil
+}
+
+// limitOr returns limit, or def if no limit is given.
+func limitOr(limit int32, def int32) int32 {
+	if limit <= 0 {
+		return def
+	}
+	return limit
+}
 
 // Client represents a CloudWatch Logs client
 type Client struct {
-	cwl *cwl.Client
+	cwl     *cwl.Client
+	options ClientOptions
+}
+
+// ClientOptions holds the settings used to build a Client.
+// Empty fields fall back to the AWS SDK defaults (environment and shared config files).
+type ClientOptions struct {
+	Profile     string
+	Region      string
+	EndpointURL string
 }
 
+// EndpointURLEnv is the environment variable overriding the CloudWatch Logs endpoint,
+// e.g. to point the client at LocalStack or another emulator.
+const EndpointURLEnv = "AWS_ENDPOINT_URL_CLOUDWATCH_LOGS"
+
 // Input types for log groups, streams, and events
 type LogGroupInput struct {
 	FilterPattern string
 	NextToken     *string
-	Ctx           context.Context
+	// Limit is the page size, DefaultPageSize if zero
+	Limit int32
+	Ctx   context.Context
 }
 type LogStreamInput struct {
-	LogGroupName  string
-	NextToken     *string
-	Ctx           context.Context
+	LogGroupName string
+	NextToken    *string
+	// Limit is the page size, DefaultPageSize if zero
+	Limit int32
+	Ctx   context.Context
 }
 type LogEventInput struct {
 	LogGroupName   string
@@ -40,7 +84,17 @@ type LogEventInput struct {
 	EndTime        time.Time
 	FilterPattern  string
 	OutputFile     string
-	Ctx            context.Context
+	OutputFormat   OutputFormat
+	// Gzip compresses the output file written by WriteLogEvents
+	Gzip      bool
+	NextToken *string
+	// Limit is the number of events per page, DefaultEventsInPage if zero
+	Limit int32
+	// Resume continues a cancelled export from its progress, appending to the output file
+	Resume *ExportProgress
+	// OnProgress is called after each page written by WriteLogEvents
+	OnProgress func(ExportProgress)
+	Ctx        context.Context
 }
 
 // Output types for log groups, streams, and events
@@ -74,23 +128,71 @@ func (c *Client) SetLogStreamOutput(lsNames []string, lsLastEvent []string, lsFi
 }
 
 // NewClient creates a new CloudWatch Logs client using the default AWS configuration.
-// It loads AWS credentials and region from the environment or AWS config files.
-func NewClient(ctx context.Context) (*Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+// It loads AWS credentials and region from the environment or AWS config files,
+// unless a profile or region is given in opts.
+// The endpoint can be overridden with opts.EndpointURL or the AWS_ENDPOINT_URL_CLOUDWATCH_LOGS
+// variable; a services section in the shared config file is honored by the SDK as well.
+func NewClient(ctx context.Context, opts ClientOptions) (*Client, error) {
+	if opts.EndpointURL == "" {
+		opts.EndpointURL = os.Getenv(EndpointURLEnv)
+	}
+	if opts.EndpointURL != "" {
+		u, err := url.Parse(opts.EndpointURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid endpoint URL %q: must be an http or https URL", opts.EndpointURL)
+		}
+	}
+
+	var loadOpts []func(*config.LoadOptions) error
+	if opts.Profile != "" {
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(opts.Profile))
+	}
+	if opts.Region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(opts.Region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load AWS config: %w", err)
 	}
 
+	opts.Region = cfg.Region
+	if cfg.Credentials != nil {
+		cfg.Credentials = aws.NewCredentialsCache(credentialsProvider{cfg.Credentials})
+	}
+
 	return &Client{
-		cwl: cwl.NewFromConfig(cfg),
+		cwl: cwl.NewFromConfig(cfg, func(o *cwl.Options) {
+			if opts.EndpointURL != "" {
+				o.BaseEndpoint = aws.String(opts.EndpointURL)
+			}
+		}),
+		options: opts,
 	}, nil
 }
 
+// Options returns the options the client was built with, with the region resolved.
+func (c *Client) Options() ClientOptions {
+	return c.options
+}
+
+// ProfileName returns the name of the profile in use,
+// taking AWS_PROFILE into account when no profile was given explicitly.
+func (c *Client) ProfileName() string {
+	if c.options.Profile != "" {
+		return c.options.Profile
+	}
+	if profile := os.Getenv("AWS_PROFILE"); profile != "" {
+		return profile
+	}
+	return "default"
+}
+
 // GetLogGroups retrieves log groups from CloudWatch Logs with optional filtering.
 // It supports pagination through the NextToken parameter.
 func (c *Client) GetLogGroups(input *LogGroupInput) (*LogGroupOutput, error) {
 	params := &cwl.DescribeLogGroupsInput{
-		Limit: aws.Int32(MaxItemsInLayout),
+		Limit: aws.Int32(limitOr(input.Limit, DefaultPageSize)),
 	}
 
 	if input.FilterPattern != "" {
@@ -117,7 +219,7 @@ func (c *Client) GetLogGroups(input *LogGroupInput) (*LogGroupOutput, error) {
 func (c *Client) GetLogStreams(input *LogStreamInput) (*LogStreamOutput, error) {
 	params := &cwl.DescribeLogStreamsInput{
 		LogGroupName: aws.String(input.LogGroupName),
-		Limit:        aws.Int32(MaxItemsInLayout),
+		Limit:        aws.Int32(limitOr(input.Limit, DefaultPageSize)),
 		OrderBy:      cwlTypes.OrderByLastEventTime,
 		Descending:   aws.Bool(true),
 	}
@@ -138,12 +240,14 @@ func (c *Client) GetLogStreams(input *LogStreamInput) (*LogStreamOutput, error)
 
 // GetLogEvents retrieves log events within the specified time range and filters.
 // It can filter by multiple log streams and supports pattern-based filtering.
+// It supports pagination through the NextToken parameter.
 func (c *Client) GetLogEvents(input *LogEventInput) (*LogEventOutput, error) {
 	params := &cwl.FilterLogEventsInput{
 		LogGroupName: aws.String(input.LogGroupName),
 		StartTime:    aws.Int64(input.StartTime.UnixMilli()),
 		EndTime:      aws.Int64(input.EndTime.UnixMilli()),
-		Limit:        aws.Int32(1000),
+		Limit:        aws.Int32(limitOr(input.Limit, DefaultEventsInPage)),
+		NextToken:    input.NextToken,
 	}
 	if len(input.LogStreamNames) > 0 {
 		params.LogStreamNames = input.LogStreamNames
@@ -164,7 +268,8 @@ func (c *Client) GetLogEvents(input *LogEventInput) (*LogEventOutput, error) {
 }
 
 // WriteLogEvents fetches all log events matching the criteria and writes them to a file.
-// It uses pagination to retrieve all events without memory limitations.
+// It uses pagination to retrieve all events without memory limitations,
+// and reports its progress after each page through input.OnProgress.
 func (c *Client) WriteLogEvents(input *LogEventInput) error {
 	params := &cwl.FilterLogEventsInput{
 		LogGroupName: aws.String(input.LogGroupName),
@@ -179,35 +284,22 @@ func (c *Client) WriteLogEvents(input *LogEventInput) error {
 		params.FilterPattern = &input.FilterPattern
 	}
 
-	outputFile := "output.txt"
-	if input.OutputFile != "" {
-		outputFile = input.OutputFile
-	}
-
-	// Create and overwrite the output file
-	file, err := os.Create(outputFile)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %v", err)
-	}
-	defer file.Close()
-
-	paginator := cwl.NewFilterLogEventsPaginator(c.cwl, params, func(o *cwl.FilterLogEventsPaginatorOptions) {
-		o.Limit = 10000
-	})
-
-	for paginator.HasMorePages() {
-		res, err := paginator.NextPage(input.Ctx)
-		if err != nil {
-			return fmt.Errorf("unable to get log events: %v", err)
-		}
+	return export(input, func(e *exporter) error {
+		params.NextToken = e.nextToken()
+		paginator := cwl.NewFilterLogEventsPaginator(c.cwl, params, func(o *cwl.FilterLogEventsPaginatorOptions) {
+			o.Limit = MaxEventsInPage
+		})
 
-		for _, event := range res.Events {
-			message := aws.ToString(event.Message)
-			_, err := file.WriteString(message)
+		for paginator.HasMorePages() {
+			res, err := paginator.NextPage(input.Ctx)
 			if err != nil {
-				return fmt.Errorf("failed to write log message: %v", err)
+				return fmt.Errorf("unable to get log events: %w", err)
+			}
+
+			if err := e.writePage(res.Events, res.NextToken); err != nil {
+				return err
 			}
 		}
-	}
-	return nil
+		return nil
+	})
 }
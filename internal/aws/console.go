@@ -0,0 +1,209 @@
+// Package aws provides AWS CloudWatch Logs client functionality for the TUI application.
+package aws
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConsoleLink is a log event view of the CloudWatch console, read from its URL.
+type ConsoleLink struct {
+	Region         string
+	LogGroupName   string
+	LogStreamNames []string
+	FilterPattern  string
+	// Relative is the duration of a relative time range up to now, zero for an absolute range
+	Relative time.Duration
+	// Start and End are the absolute time range, zero if the URL has none
+	Start time.Time
+	End   time.Time
+}
+
+// ParseConsoleURL reads the log group, stream, filter pattern, time range and region
+// of a CloudWatch console URL, such as
+//
+//	https://us-east-1.console.aws.amazon.com/cloudwatch/home?region=us-east-1#logsV2:log-groups/log-group/$252Faws$252Flambda$252Ffoo/log-events/abc$3FfilterPattern$3DERROR$26start$3D-1800000
+//
+// The console escapes the fragment twice, writing '$' for '%'.
+// The older #logEventViewer:group=G;stream=S;filter=F;start=T form is accepted as well.
+func ParseConsoleURL(raw string) (*ConsoleLink, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid console URL: %w", err)
+	}
+	if u.Fragment == "" {
+		return nil, fmt.Errorf("console URL %q does not link to a log group", raw)
+	}
+
+	link := &ConsoleLink{Region: u.Query().Get("region")}
+	if link.Region == "" {
+		// e.g. us-east-1.console.aws.amazon.com
+		if region, _, ok := strings.Cut(u.Hostname(), ".console."); ok {
+			link.Region = region
+		}
+	}
+
+	fragment, err := url.PathUnescape(strings.ReplaceAll(u.Fragment, "$", "%"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid console URL: %w", err)
+	}
+	view, params, _ := strings.Cut(fragment, ":")
+	switch view {
+	case "logsV2":
+		err = link.parseLogsV2(params)
+	case "logEventViewer":
+		err = link.parseLogEventViewer(params)
+	default:
+		err = fmt.Errorf("unsupported console page %q, link a log group or its log events", view)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if link.LogGroupName == "" {
+		return nil, fmt.Errorf("console URL %q does not link to a log group", raw)
+	}
+	return link, nil
+}
+
+// parseLogsV2 reads the fragment of the current console,
+// log-groups/log-group/G[/log-events[/S]][?filterPattern=F&start=T&end=T],
+// in which the names and the query are escaped once more.
+func (l *ConsoleLink) parseLogsV2(params string) error {
+	path, query, _ := strings.Cut(params, "?")
+	segments := strings.Split(path, "/")
+	if len(segments) < 3 || segments[0] != "log-groups" || segments[1] != "log-group" {
+		return fmt.Errorf("unsupported console page %q, link a log group or its log events", path)
+	}
+
+	group, err := url.PathUnescape(segments[2])
+	if err != nil {
+		return fmt.Errorf("invalid log group in console URL: %w", err)
+	}
+	l.LogGroupName = group
+	if len(segments) >= 5 && segments[3] == "log-events" && segments[4] != "" {
+		stream, err := url.PathUnescape(segments[4])
+		if err != nil {
+			return fmt.Errorf("invalid log stream in console URL: %w", err)
+		}
+		l.LogStreamNames = []string{stream}
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return fmt.Errorf("invalid query in console URL: %w", err)
+	}
+	l.FilterPattern = values.Get("filterPattern")
+	return l.parseTimes(values.Get("start"), values.Get("end"))
+}
+
+// parseLogEventViewer reads the fragment of the older console, group=G;stream=S;filter=F;start=T;end=T.
+func (l *ConsoleLink) parseLogEventViewer(params string) error {
+	var start, end string
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(param, "=")
+		switch key {
+		case "group":
+			l.LogGroupName = value
+		case "stream":
+			l.LogStreamNames = []string{value}
+		case "filter":
+			l.FilterPattern = value
+		case "start":
+			start = value
+		case "end":
+			end = value
+		}
+	}
+	return l.parseTimes(start, end)
+}
+
+// parseTimes reads the time range of the console: a negative start is a duration
+// in milliseconds before now, otherwise start and end are epoch milliseconds
+// or RFC 3339 times.
+func (l *ConsoleLink) parseTimes(start string, end string) error {
+	if start == "" {
+		return nil
+	}
+	if ms, err := strconv.ParseInt(start, 10, 64); err == nil && ms < 0 {
+		l.Relative = time.Duration(-ms) * time.Millisecond
+		return nil
+	}
+
+	var err error
+	if l.Start, err = parseConsoleTime(start); err != nil {
+		return err
+	}
+	l.End = time.Now()
+	if end != "" {
+		if l.End, err = parseConsoleTime(end); err != nil {
+			return err
+		}
+	}
+	if !l.Start.Before(l.End) {
+		return fmt.Errorf("time range of console URL ends before it starts")
+	}
+	return nil
+}
+
+// parseConsoleTime parses a time of the console in epoch milliseconds or RFC 3339.
+func parseConsoleTime(text string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	t, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q in console URL", text)
+	}
+	return t, nil
+}
+
+// URL returns the CloudWatch console URL of the log events of the link.
+// The console shows a single log stream or all of them, so with several log streams
+// all streams are linked. Without a region, the console uses the region last visited.
+func (l *ConsoleLink) URL() string {
+	fragment := "log-groups/log-group/" + consoleEscape(consoleEscape(l.LogGroupName)) + "/log-events"
+	if len(l.LogStreamNames) == 1 {
+		fragment += "/" + consoleEscape(consoleEscape(l.LogStreamNames[0]))
+	}
+
+	var query []string
+	if l.FilterPattern != "" {
+		query = append(query, "filterPattern="+consoleEscape(l.FilterPattern))
+	}
+	switch {
+	case l.Relative > 0:
+		query = append(query, fmt.Sprintf("start=%d", -l.Relative.Milliseconds()))
+	case !l.Start.IsZero():
+		query = append(query, fmt.Sprintf("start=%d", l.Start.UnixMilli()), fmt.Sprintf("end=%d", l.End.UnixMilli()))
+	}
+	if len(query) > 0 {
+		fragment += consoleEscape("?" + strings.Join(query, "&"))
+	}
+	fragment = strings.ReplaceAll(fragment, "%", "$")
+
+	if l.Region == "" {
+		return "https://console.aws.amazon.com/cloudwatch/home#logsV2:" + fragment
+	}
+	return fmt.Sprintf("https://%s.console.aws.amazon.com/cloudwatch/home?region=%s#logsV2:%s",
+		l.Region, url.QueryEscape(l.Region), fragment)
+}
+
+// consoleEscape escapes text like encodeURIComponent in JavaScript, as the console does.
+func consoleEscape(text string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-_.!~*'()", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+	return b.String()
+}
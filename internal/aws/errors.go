@@ -0,0 +1,95 @@
+// Package aws provides AWS CloudWatch Logs client functionality for the TUI application.
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+)
+
+// CredentialsError is an error loading the AWS credentials of a request,
+// e.g. when no credentials are configured or an SSO session expired.
+type CredentialsError struct {
+	Err error
+}
+
+// Error returns the error of the credentials provider.
+func (e *CredentialsError) Error() string {
+	return fmt.Sprintf("unable to load AWS credentials: %v", e.Err)
+}
+
+// Unwrap returns the error of the credentials provider.
+func (e *CredentialsError) Unwrap() error {
+	return e.Err
+}
+
+// credentialsProvider wraps the errors of a credentials provider in a CredentialsError,
+// as the SDK reports them as plain errors.
+type credentialsProvider struct {
+	aws.CredentialsProvider
+}
+
+// Retrieve returns the credentials of the wrapped provider.
+func (p credentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	creds, err := p.CredentialsProvider.Retrieve(ctx)
+	if err != nil {
+		return creds, &CredentialsError{Err: err}
+	}
+	return creds, nil
+}
+
+// authErrorCodes are the AWS error codes of missing, invalid or expired credentials
+// and of missing permissions.
+var authErrorCodes = map[string]bool{
+	"AccessDeniedException":       true,
+	"AccessDenied":                true,
+	"UnrecognizedClientException": true,
+	"InvalidClientTokenId":        true,
+	"InvalidSignatureException":   true,
+	"SignatureDoesNotMatch":       true,
+	"IncompleteSignature":         true,
+	"MissingAuthenticationToken":  true,
+	"ExpiredToken":                true,
+	"ExpiredTokenException":       true,
+}
+
+// ErrorDetails extracts the AWS error code (e.g. "AccessDeniedException")
+// and the request ID from an error returned by a LogsBackend.
+// Empty strings are returned for details that are not available.
+func ErrorDetails(err error) (code string, requestID string) {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		code = apiErr.ErrorCode()
+	}
+
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		requestID = respErr.ServiceRequestID()
+	}
+	return code, requestID
+}
+
+// IsAuthError returns true if err is caused by missing, invalid or expired credentials,
+// or by missing permissions.
+func IsAuthError(err error) bool {
+	// credentials that cannot be loaded fail the request before it is sent
+	var credsErr *CredentialsError
+	if errors.As(err, &credsErr) {
+		return true
+	}
+	code, _ := ErrorDetails(err)
+	return authErrorCodes[code]
+}
+
+// IsThrottlingError returns true if err is caused by exceeding the request rate,
+// even after the retries of the SDK.
+func IsThrottlingError(err error) bool {
+	code, _ := ErrorDetails(err)
+	_, ok := retry.DefaultThrottleErrorCodes[code]
+	return ok
+}
@@ -0,0 +1,415 @@
+// Package aws provides AWS CloudWatch Logs client functionality for the TUI application.
+package aws
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwlTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// OutputFormat is the file format log events are saved in.
+type OutputFormat string
+
+// Output formats for saving log events
+const (
+	// FormatAuto infers the format from the output file extension
+	FormatAuto OutputFormat = "auto"
+	// FormatRaw writes the messages as they are
+	FormatRaw OutputFormat = "raw"
+	// FormatJSONL writes one JSON object per event with all its fields
+	FormatJSONL OutputFormat = "jsonl"
+	// FormatCSV writes one CSV record per event with a header row
+	FormatCSV OutputFormat = "csv"
+	// FormatText writes one "timestamp stream message" line per event
+	FormatText OutputFormat = "text"
+)
+
+// OutputFormats lists the selectable output formats.
+var OutputFormats = []OutputFormat{FormatAuto, FormatRaw, FormatJSONL, FormatCSV, FormatText}
+
+// TimestampLayout is the layout of event timestamps in the structured output formats.
+const TimestampLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// ParseOutputFormat returns the output format with the given name.
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	for _, format := range OutputFormats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q", name)
+}
+
+// FormatFromFileName infers the output format from the extension of the file name,
+// ignoring a ".gz" extension. Unknown extensions are saved in the raw format.
+func FormatFromFileName(name string) OutputFormat {
+	name = strings.TrimSuffix(strings.ToLower(name), ".gz")
+	switch filepath.Ext(name) {
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	case ".csv":
+		return FormatCSV
+	case ".log":
+		return FormatText
+	default:
+		return FormatRaw
+	}
+}
+
+// defaultOutputFile returns the file name used when none is given.
+func defaultOutputFile(format OutputFormat) string {
+	switch format {
+	case FormatJSONL:
+		return "output.jsonl"
+	case FormatCSV:
+		return "output.csv"
+	default:
+		return "output.txt"
+	}
+}
+
+// ResolveOutput returns the output file name and the format to write it in.
+func ResolveOutput(input *LogEventInput) (string, OutputFormat) {
+	format := input.OutputFormat
+	if format == "" {
+		format = FormatAuto
+	}
+
+	outputFile := input.OutputFile
+	if outputFile == "" {
+		if format == FormatAuto {
+			format = FormatRaw
+		}
+		return defaultOutputFile(format), format
+	}
+	if format == FormatAuto {
+		format = FormatFromFileName(outputFile)
+	}
+	return outputFile, format
+}
+
+// EventWriter writes log events in an output format.
+// Flush must be called once all events are written.
+type EventWriter interface {
+	Write(events []cwlTypes.FilteredLogEvent) error
+	Flush() error
+}
+
+// NewEventWriter returns an EventWriter writing to w in the given format.
+// When appending to an existing export, headers are not written again.
+func NewEventWriter(w io.Writer, format OutputFormat, appending bool) (EventWriter, error) {
+	buf := bufio.NewWriter(w)
+	switch format {
+	case FormatRaw:
+		return &rawWriter{w: buf}, nil
+	case FormatJSONL:
+		return &jsonlWriter{w: buf, enc: json.NewEncoder(buf)}, nil
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(buf), buf: buf, wroteHeader: appending}, nil
+	case FormatText:
+		return &textWriter{w: buf}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// rawWriter writes the messages of the events as they are.
+type rawWriter struct {
+	w *bufio.Writer
+}
+
+func (r *rawWriter) Write(events []cwlTypes.FilteredLogEvent) error {
+	for _, event := range events {
+		if _, err := r.w.WriteString(aws.ToString(event.Message)); err != nil {
+			return fmt.Errorf("failed to write log message: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *rawWriter) Flush() error {
+	return r.w.Flush()
+}
+
+// jsonlEvent is the JSON representation of a FilteredLogEvent.
+type jsonlEvent struct {
+	EventId       string `json:"eventId"`
+	IngestionTime int64  `json:"ingestionTime"`
+	LogStreamName string `json:"logStreamName"`
+	Message       string `json:"message"`
+	Timestamp     int64  `json:"timestamp"`
+}
+
+// jsonlWriter writes one JSON object per event.
+type jsonlWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (j *jsonlWriter) Write(events []cwlTypes.FilteredLogEvent) error {
+	for _, event := range events {
+		err := j.enc.Encode(jsonlEvent{
+			EventId:       aws.ToString(event.EventId),
+			IngestionTime: aws.ToInt64(event.IngestionTime),
+			LogStreamName: aws.ToString(event.LogStreamName),
+			Message:       aws.ToString(event.Message),
+			Timestamp:     aws.ToInt64(event.Timestamp),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write log event: %w", err)
+		}
+	}
+	return nil
+}
+
+func (j *jsonlWriter) Flush() error {
+	return j.w.Flush()
+}
+
+// csvHeader is the header row of the CSV output format.
+var csvHeader = []string{"timestamp", "ingestionTime", "logStreamName", "eventId", "message"}
+
+// csvWriter writes one CSV record per event, preceded by a header row.
+type csvWriter struct {
+	w           *csv.Writer
+	buf         *bufio.Writer
+	wroteHeader bool
+}
+
+func (c *csvWriter) Write(events []cwlTypes.FilteredLogEvent) error {
+	if !c.wroteHeader {
+		if err := c.w.Write(csvHeader); err != nil {
+			return fmt.Errorf("failed to write csv header: %w", err)
+		}
+		c.wroteHeader = true
+	}
+	for _, event := range events {
+		err := c.w.Write([]string{
+			formatTimestamp(event.Timestamp),
+			formatTimestamp(event.IngestionTime),
+			aws.ToString(event.LogStreamName),
+			aws.ToString(event.EventId),
+			strings.TrimRight(aws.ToString(event.Message), "\r\n"),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write log event: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *csvWriter) Flush() error {
+	// an export without events still gets its header
+	if err := c.Write(nil); err != nil {
+		return err
+	}
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return err
+	}
+	return c.buf.Flush()
+}
+
+// textWriter writes one "timestamp stream message" line per event.
+type textWriter struct {
+	w *bufio.Writer
+}
+
+func (t *textWriter) Write(events []cwlTypes.FilteredLogEvent) error {
+	for _, event := range events {
+		_, err := fmt.Fprintf(t.w, "%s %s %s\n",
+			formatTimestamp(event.Timestamp),
+			aws.ToString(event.LogStreamName),
+			strings.TrimRight(aws.ToString(event.Message), "\r\n"))
+		if err != nil {
+			return fmt.Errorf("failed to write log message: %w", err)
+		}
+	}
+	return nil
+}
+
+func (t *textWriter) Flush() error {
+	return t.w.Flush()
+}
+
+// formatTimestamp formats an epoch time in milliseconds in UTC.
+func formatTimestamp(ms *int64) string {
+	if ms == nil {
+		return ""
+	}
+	return time.UnixMilli(*ms).UTC().Format(TimestampLayout)
+}
+
+// ExportProgress reports how far an export of log events got.
+type ExportProgress struct {
+	Pages  int
+	Events int
+	Bytes  int64
+	// Timestamp is the time of the newest event written so far
+	Timestamp time.Time
+	// NextToken is the token of the first page not written yet, or nil when all pages are written
+	NextToken *string
+}
+
+// Percent returns how far the export got through the time range of the input, from 0 to 100.
+func (p ExportProgress) Percent(input *LogEventInput) float64 {
+	total := input.EndTime.Sub(input.StartTime)
+	if total <= 0 || p.Timestamp.IsZero() {
+		return 0
+	}
+	percent := float64(p.Timestamp.Sub(input.StartTime)) / float64(total) * 100
+	return min(max(percent, 0), 100)
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// exporter writes the pages of an export to the output file and reports its progress.
+type exporter struct {
+	file      *os.File
+	gzip      *gzip.Writer
+	counter   *countingWriter
+	w         EventWriter
+	input     *LogEventInput
+	progress  ExportProgress
+	baseBytes int64
+}
+
+// export creates the output file of the input, writes the pages of the export with write
+// and closes the file. Closing writes the rest of the file, so its error, e.g. a full disk,
+// is returned unless write failed first.
+func export(input *LogEventInput, write func(e *exporter) error) (err error) {
+	e, err := newExporter(input)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := e.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	return write(e)
+}
+
+// newExporter creates (or truncates) the output file of the input.
+// When the input resumes a previous export, the file is appended to instead
+// and the progress continues from where it stopped.
+func newExporter(input *LogEventInput) (*exporter, error) {
+	outputFile, format := ResolveOutput(input)
+
+	e := &exporter{input: input}
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if input.Resume != nil {
+		e.progress = *input.Resume
+		e.baseBytes = input.Resume.Bytes
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	file, err := os.OpenFile(outputFile, flag, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	e.file = file
+	e.counter = &countingWriter{w: file}
+	var w io.Writer = e.counter
+	if input.Gzip {
+		// a resumed export appends a gzip member, which gzip readers concatenate
+		e.gzip = gzip.NewWriter(e.counter)
+		w = e.gzip
+	}
+	e.w, err = NewEventWriter(w, format, input.Resume != nil)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// nextToken returns the token of the page to start exporting from.
+func (e *exporter) nextToken() *string {
+	return e.progress.NextToken
+}
+
+// writePage writes a page of events and reports the progress.
+// next is the token of the following page, or nil if it was the last one.
+func (e *exporter) writePage(events []cwlTypes.FilteredLogEvent, next *string) error {
+	if err := e.w.Write(events); err != nil {
+		return err
+	}
+	// flush each page, so that a cancelled export ends on a complete page
+	if err := e.flush(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	e.progress.Pages++
+	e.progress.Events += len(events)
+	e.progress.Bytes = e.baseBytes + e.counter.n
+	for _, event := range events {
+		if ts := time.UnixMilli(aws.ToInt64(event.Timestamp)); ts.After(e.progress.Timestamp) {
+			e.progress.Timestamp = ts
+		}
+	}
+	e.progress.NextToken = next
+	if next != nil && *next == "" {
+		e.progress.NextToken = nil
+	}
+
+	if e.input.OnProgress != nil {
+		e.input.OnProgress(e.progress)
+	}
+	return nil
+}
+
+// flush writes the buffered events to the output file.
+func (e *exporter) flush() error {
+	if err := e.w.Flush(); err != nil {
+		return err
+	}
+	if e.gzip != nil {
+		return e.gzip.Flush()
+	}
+	return nil
+}
+
+// Close flushes and closes the output file.
+func (e *exporter) Close() error {
+	err := e.flush()
+	if err == nil && e.gzip != nil {
+		err = e.gzip.Close()
+	}
+	if err != nil {
+		e.file.Close()
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := e.file.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+
+	// closing gzip writes its trailer after the last page, the size of a resumed export must count it
+	if size := e.baseBytes + e.counter.n; size != e.progress.Bytes {
+		e.progress.Bytes = size
+		if e.input.OnProgress != nil {
+			e.input.OnProgress(e.progress)
+		}
+	}
+	return nil
+}
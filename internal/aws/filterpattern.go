@@ -0,0 +1,817 @@
+// Package aws provides AWS CloudWatch Logs client functionality for the TUI application.
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FilterPatternKind is the syntax a filter pattern is written in.
+type FilterPatternKind int
+
+const (
+	// FilterAll is the empty pattern, matching every event
+	FilterAll FilterPatternKind = iota
+	// FilterTerms matches terms, quoted phrases and regular expressions, e.g. `ERROR ?WARN -health "user id" %5\d\d%`
+	FilterTerms
+	// FilterJSON matches fields of JSON messages, e.g. `{ $.status = 500 }`
+	FilterJSON
+	// FilterSpaceDelimited matches fields of space-delimited messages, e.g. `[ip, user, ..., status=4*]`
+	FilterSpaceDelimited
+)
+
+// FilterPatternKindNames provides display names for each filter pattern kind.
+var FilterPatternKindNames = map[FilterPatternKind]string{
+	FilterAll:            "all events",
+	FilterTerms:          "terms",
+	FilterJSON:           "JSON",
+	FilterSpaceDelimited: "space-delimited",
+}
+
+// FilterPatternError reports a syntax error in a filter pattern.
+type FilterPatternError struct {
+	// Column is the 1-based position of the error in the pattern
+	Column  int
+	Message string
+}
+
+func (e *FilterPatternError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Message)
+}
+
+// FilterPattern is a parsed CloudWatch Logs filter pattern.
+// It matches log messages locally the way FilterLogEvents does.
+type FilterPattern struct {
+	Kind  FilterPatternKind
+	match func(message string) bool
+}
+
+// Match reports whether the message matches the pattern.
+func (f *FilterPattern) Match(message string) bool {
+	return f.match(message)
+}
+
+// ParseFilterPattern parses a filter pattern in the CloudWatch Logs syntax:
+// terms, quoted phrases, `%regex%` regular expressions, `?` optional terms and `-` excluded terms,
+// JSON patterns like `{ $.level = "error" && $.latency > 100 }`, and
+// space-delimited patterns like `[ip, user, ..., status = 5*, size > 1000]`.
+// Values of JSON and space-delimited patterns may be regular expressions too, e.g. `{ $.level = %err% }`.
+// Syntax errors are returned as *FilterPatternError.
+func ParseFilterPattern(pattern string) (*FilterPattern, error) {
+	p := &filterParser{src: pattern}
+	p.skipSpace()
+
+	var (
+		kind  FilterPatternKind
+		match func(string) bool
+		err   error
+	)
+	switch {
+	case p.eof():
+		return &FilterPattern{Kind: FilterAll, match: func(string) bool { return true }}, nil
+	case p.peek() == '{':
+		kind = FilterJSON
+		match, err = p.parseJSON()
+	case p.peek() == '[':
+		kind = FilterSpaceDelimited
+		match, err = p.parseSpaceDelimited()
+	default:
+		kind = FilterTerms
+		match, err = p.parseTerms()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q after the end of the pattern", p.rest())
+	}
+	return &FilterPattern{Kind: kind, match: match}, nil
+}
+
+// filterParser scans a filter pattern.
+type filterParser struct {
+	src string
+	pos int
+}
+
+func (p *filterParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *filterParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// rest returns the unparsed input up to the next space, for error messages.
+func (p *filterParser) rest() string {
+	rest := p.src[p.pos:]
+	if i := strings.IndexFunc(rest, unicode.IsSpace); i > 0 {
+		return rest[:i]
+	}
+	return rest
+}
+
+func (p *filterParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume skips spaces and the token s if it comes next.
+func (p *filterParser) consume(s string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// consumeWord skips spaces and the keyword if it comes next, ignoring case.
+func (p *filterParser) consumeWord(word string) bool {
+	p.skipSpace()
+	end := p.pos + len(word)
+	if end > len(p.src) || !strings.EqualFold(p.src[p.pos:end], word) {
+		return false
+	}
+	if end < len(p.src) && isIdentByte(p.src[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+// expect consumes the token s or returns an error naming what was expected.
+func (p *filterParser) expect(s string, what string) error {
+	if p.consume(s) {
+		return nil
+	}
+	if p.eof() {
+		return p.errorf("expected %s, got end of pattern", what)
+	}
+	return p.errorf("expected %s, got %q", what, p.rest())
+}
+
+// errorf returns a FilterPatternError at the current position.
+func (p *filterParser) errorf(format string, args ...any) error {
+	return p.errorAt(p.pos, format, args...)
+}
+
+func (p *filterParser) errorAt(pos int, format string, args ...any) error {
+	return &FilterPatternError{
+		Column:  utf8.RuneCountInString(p.src[:pos]) + 1,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// parseQuoted parses a double-quoted string with `\"` and `\\` escapes.
+func (p *filterParser) parseQuoted() (string, error) {
+	start := p.pos
+	p.pos++ // opening quote
+
+	var b strings.Builder
+	for !p.eof() {
+		c := p.src[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && p.pos+1 < len(p.src):
+			b.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorAt(start, "unterminated quoted string")
+}
+
+// parseRegex parses a regular expression between percent signs, like `%ERROR|WARN%`,
+// in which `\%` stands for a percent sign. Like a term, it matches anywhere in the text.
+func (p *filterParser) parseRegex() (*regexp.Regexp, error) {
+	start := p.pos
+	p.pos++ // opening percent sign
+
+	var b strings.Builder
+	for !p.eof() {
+		c := p.src[p.pos]
+		switch {
+		case c == '%':
+			p.pos++
+			if b.Len() == 0 {
+				return nil, p.errorAt(start, "empty regular expression")
+			}
+			re, err := regexp.Compile(b.String())
+			if err != nil {
+				return nil, p.errorAt(start, "invalid regular expression: %v", err)
+			}
+			return re, nil
+		case c == '\\' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '%':
+			b.WriteByte('%')
+			p.pos += 2
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return nil, p.errorAt(start, "unterminated regular expression")
+}
+
+// parseTerms parses a pattern of terms, matching messages that contain every term,
+// at least one of the `?` terms if any, and none of the `-` terms.
+func (p *filterParser) parseTerms() (func(string) bool, error) {
+	var required, optional, excluded []func(string) bool
+	for p.skipSpace(); !p.eof(); p.skipSpace() {
+		start := p.pos
+		prefix := p.peek()
+		if prefix == '?' || prefix == '-' {
+			p.pos++
+		}
+
+		var term func(string) bool
+		switch c := p.peek(); {
+		case p.eof() || unicode.IsSpace(rune(c)):
+			return nil, p.errorAt(start, "expected a term after %q", string(prefix))
+		case c == '"':
+			quoted, err := p.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			term = containsTerm(quoted)
+		case c == '%':
+			re, err := p.parseRegex()
+			if err != nil {
+				return nil, err
+			}
+			term = re.MatchString
+		case c == '{' || c == '[':
+			return nil, p.errorf("%q must start the pattern; quote it to match it as a term", string(c))
+		default:
+			termStart := p.pos
+			for !p.eof() && !unicode.IsSpace(rune(p.peek())) {
+				if p.peek() == '"' {
+					return nil, p.errorf("unexpected quote inside a term; quote the whole term instead")
+				}
+				p.pos++
+			}
+			term = containsTerm(p.src[termStart:p.pos])
+		}
+
+		switch prefix {
+		case '?':
+			optional = append(optional, term)
+		case '-':
+			excluded = append(excluded, term)
+		default:
+			required = append(required, term)
+		}
+	}
+
+	return func(message string) bool {
+		for _, term := range required {
+			if !term(message) {
+				return false
+			}
+		}
+		for _, term := range excluded {
+			if term(message) {
+				return false
+			}
+		}
+		if len(optional) == 0 {
+			return true
+		}
+		for _, term := range optional {
+			if term(message) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// containsTerm returns a matcher of the messages containing the term.
+func containsTerm(term string) func(string) bool {
+	return func(message string) bool {
+		return strings.Contains(message, term)
+	}
+}
+
+// condition compares a field against a value, e.g. `= "error"` or `> 100`.
+type condition struct {
+	op     string
+	number float64
+	// isNumber is true if the value is a number, otherwise it is a string matched by glob,
+	// which is the regular expression itself for a `%regex%` value
+	isNumber bool
+	glob     *regexp.Regexp
+}
+
+// comparisonOps lists the comparison operators, longest first.
+var comparisonOps = []string{"!=", "<=", ">=", "=", "<", ">"}
+
+// parseCondition parses a comparison operator and its value.
+func (p *filterParser) parseCondition() (condition, error) {
+	p.skipSpace()
+	var c condition
+	for _, op := range comparisonOps {
+		if p.consume(op) {
+			c.op = op
+			break
+		}
+	}
+	if c.op == "" {
+		if p.eof() {
+			return c, p.errorf("expected a comparison operator, got end of pattern")
+		}
+		return c, p.errorf("expected a comparison operator (=, !=, <, <=, >, >=), got %q", p.rest())
+	}
+
+	p.skipSpace()
+	start := p.pos
+	var value string
+	quoted := false
+	switch {
+	case p.eof():
+		return c, p.errorf("expected a value after %q, got end of pattern", c.op)
+	case p.peek() == '%':
+		re, err := p.parseRegex()
+		if err != nil {
+			return c, err
+		}
+		if c.op != "=" && c.op != "!=" {
+			return c, p.errorAt(start, "%q needs a number, got a regular expression", c.op)
+		}
+		c.glob = re
+		return c, nil
+	case p.peek() == '"':
+		v, err := p.parseQuoted()
+		if err != nil {
+			return c, err
+		}
+		value, quoted = v, true
+	default:
+		for !p.eof() && !strings.ContainsRune(" \t\n,)}]&|", rune(p.peek())) {
+			p.pos++
+		}
+		value = p.src[start:p.pos]
+		if value == "" {
+			return c, p.errorf("expected a value after %q, got %q", c.op, p.rest())
+		}
+	}
+
+	if !quoted {
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			c.number, c.isNumber = n, true
+			return c, nil
+		}
+	}
+	if c.op != "=" && c.op != "!=" {
+		return c, p.errorAt(start, "%q needs a number, got %q", c.op, value)
+	}
+	c.glob = globRegexp(value)
+	return c, nil
+}
+
+// globRegexp returns a regexp matching the whole of strings matching the glob,
+// where `*` matches any sequence of characters.
+func globRegexp(glob string) *regexp.Regexp {
+	parts := strings.Split(glob, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile(`^(?s)` + strings.Join(parts, ".*") + `$`)
+}
+
+// matchNumber compares a number against the value of the condition.
+func (c condition) matchNumber(n float64) bool {
+	if !c.isNumber {
+		return false
+	}
+	switch c.op {
+	case "=":
+		return n == c.number
+	case "!=":
+		return n != c.number
+	case "<":
+		return n < c.number
+	case "<=":
+		return n <= c.number
+	case ">":
+		return n > c.number
+	default:
+		return n >= c.number
+	}
+}
+
+// matchString compares a string against the value of the condition.
+func (c condition) matchString(s string) bool {
+	if c.isNumber {
+		return false
+	}
+	return c.glob.MatchString(s) == (c.op == "=")
+}
+
+// jsonMatcher matches a decoded JSON message.
+type jsonMatcher func(doc any) bool
+
+// parseJSON parses a JSON pattern `{ expression }`.
+func (p *filterParser) parseJSON() (func(string) bool, error) {
+	p.pos++ // opening brace
+	match, err := p.parseJSONOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("}", `"&&", "||" or "}"`); err != nil {
+		return nil, err
+	}
+
+	return func(message string) bool {
+		dec := json.NewDecoder(strings.NewReader(message))
+		dec.UseNumber()
+		var doc any
+		if err := dec.Decode(&doc); err != nil {
+			return false
+		}
+		return match(doc)
+	}, nil
+}
+
+func (p *filterParser) parseJSONOr() (jsonMatcher, error) {
+	left, err := p.parseJSONAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("||") {
+		right, err := p.parseJSONAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(doc any) bool { return l(doc) || right(doc) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseJSONAnd() (jsonMatcher, error) {
+	left, err := p.parseJSONUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("&&") {
+		right, err := p.parseJSONUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(doc any) bool { return l(doc) && right(doc) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseJSONUnary() (jsonMatcher, error) {
+	if p.consume("(") {
+		match, err := p.parseJSONOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")", `"&&", "||" or ")"`); err != nil {
+			return nil, err
+		}
+		return match, nil
+	}
+
+	selector, err := p.parseSelector()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.consumeWord("IS"):
+		var want func(v any) bool
+		switch {
+		case p.consumeWord("TRUE"):
+			want = func(v any) bool { return v == true }
+		case p.consumeWord("FALSE"):
+			want = func(v any) bool { return v == false }
+		case p.consumeWord("NULL"):
+			want = func(v any) bool { return v == nil }
+		default:
+			return nil, p.errorf("expected TRUE, FALSE or NULL after IS")
+		}
+		return func(doc any) bool {
+			for _, v := range selector(doc) {
+				if want(v) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case p.consumeWord("NOT"):
+		if !p.consumeWord("EXISTS") {
+			return nil, p.errorf("expected EXISTS after NOT")
+		}
+		return func(doc any) bool { return len(selector(doc)) == 0 }, nil
+	case p.consumeWord("EXISTS"):
+		return func(doc any) bool { return len(selector(doc)) > 0 }, nil
+	}
+
+	c, err := p.parseCondition()
+	if err != nil {
+		return nil, err
+	}
+	return func(doc any) bool {
+		for _, v := range selector(doc) {
+			switch v := v.(type) {
+			case string:
+				if c.matchString(v) {
+					return true
+				}
+			case json.Number:
+				if n, err := v.Float64(); err == nil && c.matchNumber(n) {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
+
+// parseSelector parses a JSON selector like `$.user.id`, `$.items[0].name` or `$.tags[*]`.
+// The returned function yields the selected values of a document.
+func (p *filterParser) parseSelector() (func(doc any) []any, error) {
+	p.skipSpace()
+	if p.eof() {
+		return nil, p.errorf("expected a selector like $.field, got end of pattern")
+	}
+	if p.peek() != '$' {
+		return nil, p.errorf("expected a selector like $.field, got %q", p.rest())
+	}
+	p.pos++
+
+	type step struct {
+		key   string
+		index int
+		// all is true for `[*]`
+		all     bool
+		isIndex bool
+	}
+	var steps []step
+	for {
+		switch p.peek() {
+		case '.':
+			p.pos++
+			start := p.pos
+			for !p.eof() && isIdentByte(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("expected a field name after \".\"")
+			}
+			steps = append(steps, step{key: p.src[start:p.pos]})
+			continue
+		case '[':
+			p.pos++
+			start := p.pos
+			if p.consume("*") {
+				steps = append(steps, step{all: true, isIndex: true})
+			} else {
+				for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+					p.pos++
+				}
+				if start == p.pos {
+					return nil, p.errorf("expected an array index or * after \"[\"")
+				}
+				index, _ := strconv.Atoi(p.src[start:p.pos])
+				steps = append(steps, step{index: index, isIndex: true})
+			}
+			if p.peek() != ']' {
+				return nil, p.errorf("expected \"]\" to close the array index")
+			}
+			p.pos++
+			continue
+		}
+		break
+	}
+	if len(steps) == 0 {
+		return nil, p.errorf("expected a field after \"$\", like $.field")
+	}
+
+	return func(doc any) []any {
+		values := []any{doc}
+		for _, s := range steps {
+			var next []any
+			for _, v := range values {
+				switch {
+				case !s.isIndex:
+					if obj, ok := v.(map[string]any); ok {
+						if field, ok := obj[s.key]; ok {
+							next = append(next, field)
+						}
+					}
+				case s.all:
+					if arr, ok := v.([]any); ok {
+						next = append(next, arr...)
+					}
+				default:
+					if arr, ok := v.([]any); ok && s.index < len(arr) {
+						next = append(next, arr[s.index])
+					}
+				}
+			}
+			values = next
+		}
+		return values
+	}, nil
+}
+
+// spaceField is a field of a space-delimited pattern.
+type spaceField struct {
+	name string
+	// ellipsis is true for `...`, matching any number of fields
+	ellipsis bool
+	// anyOf holds alternatives of conditions that must all hold; empty matches any value
+	anyOf [][]condition
+}
+
+// match reports whether a field value satisfies the conditions.
+func (f spaceField) match(value string) bool {
+	if len(f.anyOf) == 0 {
+		return true
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	isNumber := err == nil
+	for _, all := range f.anyOf {
+		ok := true
+		for _, c := range all {
+			if c.isNumber {
+				ok = isNumber && c.matchNumber(n)
+			} else {
+				ok = c.matchString(value)
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSpaceDelimited parses a space-delimited pattern `[field, field = value, ...]`.
+func (p *filterParser) parseSpaceDelimited() (func(string) bool, error) {
+	p.pos++ // opening bracket
+
+	var fields []spaceField
+	for {
+		field, err := p.parseSpaceField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		if p.consume("]") {
+			break
+		}
+		if err := p.expect(",", `"," or "]"`); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(message string) bool {
+		return matchSpaceFields(fields, splitSpaceFields(message))
+	}, nil
+}
+
+func (p *filterParser) parseSpaceField() (spaceField, error) {
+	if p.consume("...") {
+		return spaceField{ellipsis: true}, nil
+	}
+
+	name, err := p.parseFieldName()
+	if err != nil {
+		return spaceField{}, err
+	}
+	field := spaceField{name: name}
+
+	p.skipSpace()
+	if p.eof() || p.peek() == ',' || p.peek() == ']' {
+		return field, nil
+	}
+
+	var all []condition
+	for {
+		c, err := p.parseCondition()
+		if err != nil {
+			return field, err
+		}
+		all = append(all, c)
+
+		switch {
+		case p.consume("&&"):
+		case p.consume("||"):
+			field.anyOf = append(field.anyOf, all)
+			all = nil
+		default:
+			field.anyOf = append(field.anyOf, all)
+			return field, nil
+		}
+
+		p.skipSpace()
+		start := p.pos
+		other, err := p.parseFieldName()
+		if err != nil {
+			return field, err
+		}
+		if other != name {
+			return field, p.errorAt(start, "condition on %q inside field %q; conditions of a field must refer to the field itself", other, name)
+		}
+	}
+}
+
+// parseFieldName parses the name of a space-delimited field.
+func (p *filterParser) parseFieldName() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	for !p.eof() && isIdentByte(p.peek()) {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.eof() {
+			return "", p.errorf("expected a field name or ..., got end of pattern")
+		}
+		return "", p.errorf("expected a field name or ..., got %q", p.rest())
+	}
+	return p.src[start:p.pos], nil
+}
+
+// isIdentByte reports whether c may be part of a field name.
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '-' || c == '@' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// splitSpaceFields splits a message into space-delimited fields.
+// Text in double quotes or square brackets is a single field.
+func splitSpaceFields(message string) []string {
+	var fields []string
+	for i := 0; i < len(message); {
+		switch c := message[i]; {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '"' || c == '[':
+			closing := byte('"')
+			if c == '[' {
+				closing = ']'
+			}
+			end := strings.IndexByte(message[i+1:], closing)
+			if end < 0 {
+				fields = append(fields, message[i+1:])
+				return fields
+			}
+			fields = append(fields, message[i+1:i+1+end])
+			i += end + 2
+		default:
+			end := strings.IndexFunc(message[i:], unicode.IsSpace)
+			if end < 0 {
+				fields = append(fields, message[i:])
+				return fields
+			}
+			fields = append(fields, message[i:i+end])
+			i += end
+		}
+	}
+	return fields
+}
+
+// matchSpaceFields matches the fields of a message against the pattern fields,
+// where an ellipsis may stand for any number of fields.
+func matchSpaceFields(pattern []spaceField, values []string) bool {
+	if len(pattern) == 0 {
+		return len(values) == 0
+	}
+	if pattern[0].ellipsis {
+		for i := 0; i <= len(values); i++ {
+			if matchSpaceFields(pattern[1:], values[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(values) == 0 || !pattern[0].match(values[0]) {
+		return false
+	}
+	return matchSpaceFields(pattern[1:], values[1:])
+}
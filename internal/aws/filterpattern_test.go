@@ -0,0 +1,171 @@
+package aws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseFilterPatternKind(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    FilterPatternKind
+	}{
+		{"", FilterAll},
+		{"   ", FilterAll},
+		{"ERROR", FilterTerms},
+		{`"user id"`, FilterTerms},
+		{"{ $.level = error }", FilterJSON},
+		{"[ip, user]", FilterSpaceDelimited},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilterPattern(tt.pattern)
+		if err != nil {
+			t.Errorf("ParseFilterPattern(%q): %v", tt.pattern, err)
+			continue
+		}
+		if f.Kind != tt.want {
+			t.Errorf("ParseFilterPattern(%q).Kind = %s, want %s",
+				tt.pattern, FilterPatternKindNames[f.Kind], FilterPatternKindNames[tt.want])
+		}
+	}
+}
+
+func TestFilterPatternMatch(t *testing.T) {
+	const accessLog = `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.0" 500 2326`
+
+	tests := []struct {
+		name    string
+		pattern string
+		message string
+		want    bool
+	}{
+		{"empty matches all", "", "anything", true},
+
+		{"term", "ERROR", "an ERROR occurred", true},
+		{"term is case sensitive", "ERROR", "an error occurred", false},
+		{"all terms", "ERROR timeout", "ERROR: read timeout", true},
+		{"all terms missing one", "ERROR timeout", "ERROR: disk full", false},
+		{"optional term", "ERROR ?timeout ?refused", "ERROR: connection refused", true},
+		{"no optional term", "ERROR ?timeout ?refused", "ERROR: disk full", false},
+		{"only optional terms", "?WARN ?ERROR", "WARN low memory", true},
+		{"excluded term", "ERROR -health", "ERROR: health check failed", false},
+		{"not excluded term", "ERROR -health", "ERROR: request failed", true},
+		{"quoted phrase", `"user id"`, "bad user id 5", true},
+		{"quoted phrase in order", `"user id"`, "user 5 has no id", false},
+		{"quoted escapes", `"say \"hi\""`, `they say "hi" twice`, true},
+		{"excluded phrase", `-"health check"`, "GET /health check", false},
+		{"optional phrase", `?"read timeout" ?refused`, "read timeout after 5s", true},
+		{"regex", "%ERROR|WARN%", "WARN disk", true},
+		{"regex mismatch", "%ERROR|WARN%", "INFO ok", false},
+		{"regex with term", `ERROR %timeout after \d+s%`, "ERROR: timeout after 5s", true},
+		{"regex with spaces mismatch", `ERROR %timeout after \d+s%`, "ERROR: timeout after s", false},
+		{"optional regex", `?%5\d\d% ?refused`, "status 503", true},
+		{"excluded regex", "-%health.?check%", "GET /healthcheck", false},
+		{"regex percent sign", `%100\%%`, "cpu at 100%", true},
+
+		{"json string", `{ $.level = "error" }`, `{"level":"error"}`, true},
+		{"json unquoted string", `{ $.level = error }`, `{"level":"error"}`, true},
+		{"json string mismatch", `{ $.level = "error" }`, `{"level":"info"}`, false},
+		{"json glob", `{ $.msg = "time*" }`, `{"msg":"timeout after 5s"}`, true},
+		{"json not equal", `{ $.level != "error" }`, `{"level":"info"}`, true},
+		{"json number", `{ $.status = 500 }`, `{"status":500}`, true},
+		{"json number is not a string", `{ $.status = 500 }`, `{"status":"500"}`, false},
+		{"json greater", `{ $.latency > 100 }`, `{"latency":150.5}`, true},
+		{"json greater or equal", `{ $.latency >= 100 }`, `{"latency":100}`, true},
+		{"json less", `{ $.latency < 100 }`, `{"latency":100}`, false},
+		{"json and", `{ $.level = "error" && $.latency > 100 }`, `{"level":"error","latency":150}`, true},
+		{"json and one false", `{ $.level = "error" && $.latency > 100 }`, `{"level":"error","latency":50}`, false},
+		{"json or", `{ $.level = "error" || $.status = 500 }`, `{"level":"info","status":500}`, true},
+		{"json or both false", `{ $.level = "error" || $.status = 500 }`, `{"level":"info","status":200}`, false},
+		{"json and binds tighter", `{ $.a = 1 || $.b = 2 && $.c = 3 }`, `{"a":1}`, true},
+		{"json and binds tighter false", `{ $.a = 1 || $.b = 2 && $.c = 3 }`, `{"b":2}`, false},
+		{"json parentheses", `{ ($.a = 1 || $.b = 2) && $.c = 3 }`, `{"b":2,"c":3}`, true},
+		{"json parentheses false", `{ ($.a = 1 || $.b = 2) && $.c = 3 }`, `{"a":1}`, false},
+		{"json nested field", `{ $.user.id = 7 }`, `{"user":{"id":7}}`, true},
+		{"json array index", `{ $.items[1].name = "b" }`, `{"items":[{"name":"a"},{"name":"b"}]}`, true},
+		{"json any array element", `{ $.tags[*] = "prod" }`, `{"tags":["dev","prod"]}`, true},
+		{"json exists", `{ $.trace EXISTS }`, `{"trace":null}`, true},
+		{"json not exists", `{ $.trace NOT EXISTS }`, `{"trace":"abc"}`, false},
+		{"json is true", `{ $.ok IS TRUE }`, `{"ok":true}`, true},
+		{"json is null", `{ $.ok IS NULL }`, `{"ok":false}`, false},
+		{"json missing field", `{ $.level = "error" }`, `{"msg":"error"}`, false},
+		{"json regex", "{ $.level = %err% }", `{"level":"error"}`, true},
+		{"json regex mismatch", "{ $.level = %err% }", `{"level":"info"}`, false},
+		{"json regex not equal", "{ $.level != %^debug$% }", `{"level":"info"}`, true},
+		{"json regex with or", `{ $.msg = %time|refus% || $.status = 500 }`, `{"msg":"connection refused"}`, true},
+		{"json on plain text", `{ $.level = "error" }`, "level=error", false},
+
+		{"space fields", "[ip, ident, user, date, request, status, size]", accessLog, true},
+		{"space field count", "[ip, user]", accessLog, false},
+		{"space ellipsis", "[ip, ..., status, size]", accessLog, true},
+		{"space glob", "[ip, ..., status = 5*, size]", accessLog, true},
+		{"space glob mismatch", "[ip, ..., status = 4*, size]", accessLog, false},
+		{"space and", "[ip, ..., status = 5*, size > 1000 && size < 5000]", accessLog, true},
+		{"space and false", "[ip, ..., status = 5*, size > 1000 && size < 2000]", accessLog, false},
+		{"space or", "[ip, ..., status = 404 || status = 500, size]", accessLog, true},
+		{"space bracketed field", "[ip, ident, user = frank, date = *2000*, ...]", accessLog, true},
+		{"space quoted field", `[..., request = "GET /a.gif*", status, size]`, accessLog, true},
+		{"space number on text", "[ip, ..., status, size > 10]", "127.0.0.1 500 big", false},
+		{"space regex", `[ip, ..., status = %^5\d\d$%, size]`, accessLog, true},
+		{"space regex mismatch", "[ip, ..., status = %^4%, size]", accessLog, false},
+		{"space regex with comma", "[ip, ..., request = %GET /a,?%, status, size]", accessLog, true},
+		{"space not equal", "[level != DEBUG, msg]", "INFO started", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseFilterPattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("ParseFilterPattern(%q): %v", tt.pattern, err)
+			}
+			if got := f.Match(tt.message); got != tt.want {
+				t.Errorf("%q matching %q = %v, want %v", tt.pattern, tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterPatternError(t *testing.T) {
+	tests := []struct {
+		pattern string
+		column  int
+		message string
+	}{
+		{"?", 1, `expected a term after "?"`},
+		{"ERROR -", 7, `expected a term after "-"`},
+		{`ERROR "user id`, 7, "unterminated quoted string"},
+		{`ab"c"`, 3, "unexpected quote inside a term"},
+		{"ERROR {", 7, `"{" must start the pattern`},
+		{"{ $.a = 1", 10, `expected "&&", "||" or "}", got end of pattern`},
+		{"{ $.a = 1 } x", 13, `unexpected "x" after the end of the pattern`},
+		{"{ a = 1 }", 3, `expected a selector like $.field, got "a"`},
+		{"{ $. = 1 }", 5, `expected a field name after "."`},
+		{"{ $.a ~ 1 }", 7, "expected a comparison operator"},
+		{"{ $.a = }", 9, `expected a value after "=", got "}"`},
+		{`{ $.a > "x" }`, 9, `">" needs a number, got "x"`},
+		{"{ ($.a = 1 }", 12, `expected "&&", "||" or ")"`},
+		{"{ $.a IS MAYBE }", 10, "expected TRUE, FALSE or NULL after IS"},
+		{"%ERROR", 1, "unterminated regular expression"},
+		{"ERROR %(a%", 7, "invalid regular expression"},
+		{"%%", 1, "empty regular expression"},
+		{"{ $.n > %5% }", 9, `">" needs a number, got a regular expression`},
+		{"{ $.level = %err }", 13, "unterminated regular expression"},
+		{"[ip, status = 5*", 17, `expected "," or "]", got end of pattern`},
+		{"[ip, , size]", 6, `expected a field name or ..., got ","`},
+		{"[a, a = 1 && b = 2]", 14, `condition on "b" inside field "a"`},
+		{"[a, b > x]", 9, `">" needs a number, got "x"`},
+		// columns count characters, not bytes
+		{"é ?", 3, `expected a term after "?"`},
+	}
+	for _, tt := range tests {
+		_, err := ParseFilterPattern(tt.pattern)
+		var perr *FilterPatternError
+		if !errors.As(err, &perr) {
+			t.Errorf("ParseFilterPattern(%q) error = %v, want a *FilterPatternError", tt.pattern, err)
+			continue
+		}
+		if perr.Column != tt.column || !strings.Contains(perr.Message, tt.message) {
+			t.Errorf("ParseFilterPattern(%q) error = %q, want column %d: %q", tt.pattern, err, tt.column, tt.message)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package aws provides AWS CloudWatch Logs client functionality for the TUI application.
+package aws
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwlTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// FollowOverlap is how far before the newest seen event each follow poll starts,
+// so events ingested late are still picked up. Duplicates are dropped by event ID.
+const FollowOverlap = 30 * time.Second
+
+// Follower tracks the log events seen while following a query like `tail -f`,
+// for the log viewer and the tail subcommand alike.
+type Follower struct {
+	// start is the start of the followed time range, no poll starts before it
+	start  int64
+	newest int64
+	// seen holds the timestamps of the seen events by log group and ID
+	seen map[string]int64
+}
+
+// NewFollower returns a Follower of the log events from start on.
+func NewFollower(start time.Time) *Follower {
+	return &Follower{
+		start:  start.UnixMilli(),
+		newest: start.UnixMilli(),
+		seen:   make(map[string]int64),
+	}
+}
+
+// From returns the start time of the next poll: FollowOverlap before the newest seen event,
+// but not before the start of the followed time range.
+func (f *Follower) From() time.Time {
+	return time.UnixMilli(max(f.start, f.newest-FollowOverlap.Milliseconds()))
+}
+
+// Unseen returns the events of the log group that were not seen before, and remembers them.
+// Events no poll can return anymore are forgotten.
+func (f *Follower) Unseen(group string, events []cwlTypes.FilteredLogEvent) []cwlTypes.FilteredLogEvent {
+	var unseen []cwlTypes.FilteredLogEvent
+	for _, event := range events {
+		// event IDs are only unique within a log group
+		key := group + "/" + aws.ToString(event.EventId)
+		if _, ok := f.seen[key]; ok {
+			continue
+		}
+		ts := aws.ToInt64(event.Timestamp)
+		f.seen[key] = ts
+		f.newest = max(f.newest, ts)
+		unseen = append(unseen, event)
+	}
+
+	oldest := f.From().UnixMilli()
+	for key, ts := range f.seen {
+		if ts < oldest {
+			delete(f.seen, key)
+		}
+	}
+	return unseen
+}
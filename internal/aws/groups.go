@@ -0,0 +1,80 @@
+// Package aws provides AWS CloudWatch Logs client functionality for the TUI application.
+package aws
+
+import (
+	"fmt"
+	"sync"
+)
+
+// GroupError is an error fetching the log events of one of several log groups.
+type GroupError struct {
+	LogGroupName string
+	Err          error
+}
+
+// Error returns the log group name followed by the error.
+func (e *GroupError) Error() string {
+	return fmt.Sprintf("%s: %v", e.LogGroupName, e.Err)
+}
+
+// Unwrap returns the error of the log group, e.g. to read its AWS error details.
+func (e *GroupError) Unwrap() error {
+	return e.Err
+}
+
+// GetLogEventsOfGroups fetches a page of log events for each input concurrently,
+// typically one input per log group. The outputs and errors are in the order of
+// the inputs: a failed input has a nil output and a GroupError.
+func GetLogEventsOfGroups(backend LogsBackend, inputs []*LogEventInput) ([]*LogEventOutput, []error) {
+	return getOfGroups(inputs, backend.GetLogEvents)
+}
+
+// GetAllLogEventsOfGroups fetches all pages of log events for each input concurrently,
+// like GetLogEventsOfGroups.
+func GetAllLogEventsOfGroups(backend LogsBackend, inputs []*LogEventInput) ([]*LogEventOutput, []error) {
+	return getOfGroups(inputs, func(input *LogEventInput) (*LogEventOutput, error) {
+		return GetAllLogEvents(backend, input)
+	})
+}
+
+// GetAllLogEvents fetches all pages of log events of the input, following the next page token
+// until it runs out. FilterLogEvents may return a page with fewer events, even none, while more remain,
+// so a full page does not mean the last one.
+func GetAllLogEvents(backend LogsBackend, input *LogEventInput) (*LogEventOutput, error) {
+	page := *input
+	all := &LogEventOutput{}
+	for {
+		output, err := backend.GetLogEvents(&page)
+		if err != nil {
+			return nil, err
+		}
+		all.LogEvents = append(all.LogEvents, output.LogEvents...)
+		if output.NextToken == nil {
+			return all, nil
+		}
+		page.NextToken = output.NextToken
+	}
+}
+
+// getOfGroups runs get for each input concurrently and returns the outputs and errors
+// in the order of the inputs.
+func getOfGroups(inputs []*LogEventInput, get func(*LogEventInput) (*LogEventOutput, error)) ([]*LogEventOutput, []error) {
+	outputs := make([]*LogEventOutput, len(inputs))
+	errs := make([]error, len(inputs))
+
+	var wg sync.WaitGroup
+	for i, input := range inputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			output, err := get(input)
+			if err != nil {
+				errs[i] = &GroupError{LogGroupName: input.LogGroupName, Err: err}
+				return
+			}
+			outputs[i] = output
+		}()
+	}
+	wg.Wait()
+	return outputs, errs
+}
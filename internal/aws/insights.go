@@ -0,0 +1,103 @@
+// Package aws provides AWS CloudWatch Logs client functionality for the TUI application.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwl "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cwlTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// MaxQueryResults is the maximum number of rows requested from a Logs Insights query.
+const MaxQueryResults int32 = 1000
+
+// Input types for Logs Insights queries
+type QueryInput struct {
+	LogGroupNames []string
+	QueryString   string
+	StartTime     time.Time
+	EndTime       time.Time
+	Ctx           context.Context
+}
+type QueryResultsInput struct {
+	QueryId string
+	Ctx     context.Context
+}
+
+// Output types for Logs Insights queries
+type QueryOutput struct {
+	QueryId string
+}
+type QueryResultsOutput struct {
+	Status         cwlTypes.QueryStatus
+	Results        [][]cwlTypes.ResultField
+	RecordsMatched float64
+	RecordsScanned float64
+	BytesScanned   float64
+}
+
+// IsDone returns true if the query reached a final status and will not produce more results.
+func (o *QueryResultsOutput) IsDone() bool {
+	switch o.Status {
+	case cwlTypes.QueryStatusComplete,
+		cwlTypes.QueryStatusFailed,
+		cwlTypes.QueryStatusCancelled,
+		cwlTypes.QueryStatusTimeout:
+		return true
+	}
+	return false
+}
+
+// StartQuery schedules a Logs Insights query over the given log groups and time range.
+func (c *Client) StartQuery(input *QueryInput) (*QueryOutput, error) {
+	params := &cwl.StartQueryInput{
+		LogGroupNames: input.LogGroupNames,
+		QueryString:   aws.String(input.QueryString),
+		StartTime:     aws.Int64(input.StartTime.Unix()),
+		EndTime:       aws.Int64(input.EndTime.Unix()),
+		Limit:         aws.Int32(MaxQueryResults),
+	}
+
+	res, err := c.cwl.StartQuery(input.Ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start query: %w", err)
+	}
+	return &QueryOutput{
+		QueryId: aws.ToString(res.QueryId),
+	}, nil
+}
+
+// GetQueryResults returns the status, statistics and (possibly partial) results of a query.
+func (c *Client) GetQueryResults(input *QueryResultsInput) (*QueryResultsOutput, error) {
+	res, err := c.cwl.GetQueryResults(input.Ctx, &cwl.GetQueryResultsInput{
+		QueryId: aws.String(input.QueryId),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get query results: %w", err)
+	}
+
+	output := &QueryResultsOutput{
+		Status:  res.Status,
+		Results: res.Results,
+	}
+	if res.Statistics != nil {
+		output.RecordsMatched = res.Statistics.RecordsMatched
+		output.RecordsScanned = res.Statistics.RecordsScanned
+		output.BytesScanned = res.Statistics.BytesScanned
+	}
+	return output, nil
+}
+
+// StopQuery cancels a running query.
+func (c *Client) StopQuery(input *QueryResultsInput) error {
+	_, err := c.cwl.StopQuery(input.Ctx, &cwl.StopQueryInput{
+		QueryId: aws.String(input.QueryId),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stop query: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+// Package aws provides AWS CloudWatch Logs client functionality for the TUI application.
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwlTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// Fixture describes the log groups, streams and events served by a MemoryBackend.
+// Fixture files are JSON documents with this structure.
+type Fixture struct {
+	LogGroups []FixtureLogGroup `json:"logGroups"`
+}
+
+// FixtureLogGroup is a log group entry in a fixture file.
+type FixtureLogGroup struct {
+	Name            string             `json:"name"`
+	RetentionInDays int32              `json:"retentionInDays"`
+	StoredBytes     int64              `json:"storedBytes"`
+	LogStreams      []FixtureLogStream `json:"logStreams"`
+}
+
+// FixtureLogStream is a log stream entry in a fixture file.
+type FixtureLogStream struct {
+	Name   string         `json:"name"`
+	Events []FixtureEvent `json:"events"`
+}
+
+// FixtureEvent is a single log event in a fixture file.
+// Either Timestamp (milliseconds since epoch) or Ago (a Go duration
+// relative to the time the fixture is loaded, e.g. "15m") should be set.
+type FixtureEvent struct {
+	Timestamp int64  `json:"timestamp"`
+	Ago       string `json:"ago"`
+	Message   string `json:"message"`
+}
+
+// MemoryBackend is an in-memory LogsBackend seeded from fixture files.
+// It lets the TUI run without AWS access and gives tests deterministic data.
+type MemoryBackend struct {
+	logGroups []cwlTypes.LogGroup
+	streams   map[string][]cwlTypes.LogStream
+	events    map[string][]cwlTypes.FilteredLogEvent
+	queries   map[string]*QueryResultsOutput
+	mu        sync.Mutex
+}
+
+// NewMemoryBackend creates a MemoryBackend from the given fixture files.
+// A path may also be a directory, in which case every *.json file in it is loaded.
+func NewMemoryBackend(paths ...string) (*MemoryBackend, error) {
+	m := &MemoryBackend{
+		streams: make(map[string][]cwlTypes.LogStream),
+		events:  make(map[string][]cwlTypes.FilteredLogEvent),
+		queries: make(map[string]*QueryResultsOutput),
+	}
+
+	now := time.Now()
+	for _, path := range paths {
+		files, err := fixtureFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			fixture, err := readFixture(file)
+			if err != nil {
+				return nil, err
+			}
+			if err := m.AddFixture(fixture, now); err != nil {
+				return nil, fmt.Errorf("invalid fixture %s: %w", file, err)
+			}
+		}
+	}
+	return m, nil
+}
+
+// fixtureFiles expands a fixture path into the list of files to load.
+func fixtureFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read fixture: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list fixtures: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readFixture decodes a single fixture file.
+func readFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read fixture: %w", err)
+	}
+
+	fixture := &Fixture{}
+	if err := json.Unmarshal(data, fixture); err != nil {
+		return nil, fmt.Errorf("unable to parse fixture %s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+// AddFixture adds the log groups of a fixture to the backend.
+// Relative event times are resolved against now.
+func (m *MemoryBackend) AddFixture(fixture *Fixture, now time.Time) error {
+	for _, lg := range fixture.LogGroups {
+		if lg.Name == "" {
+			return fmt.Errorf("log group without name")
+		}
+		if _, ok := m.streams[lg.Name]; !ok {
+			m.logGroups = append(m.logGroups, cwlTypes.LogGroup{
+				LogGroupName:    aws.String(lg.Name),
+				RetentionInDays: aws.Int32(lg.RetentionInDays),
+				StoredBytes:     aws.Int64(lg.StoredBytes),
+			})
+			m.streams[lg.Name] = []cwlTypes.LogStream{}
+		}
+
+		for _, ls := range lg.LogStreams {
+			// a stream may be spread over several fixtures, its event IDs continue
+			// the numbering of the events already added, as they must be unique in the group
+			seen := 0
+			for _, event := range m.events[lg.Name] {
+				if aws.ToString(event.LogStreamName) == ls.Name {
+					seen++
+				}
+			}
+
+			var first, last int64
+			for i, fe := range ls.Events {
+				ts := fe.Timestamp
+				if fe.Ago != "" {
+					ago, err := time.ParseDuration(fe.Ago)
+					if err != nil {
+						return fmt.Errorf("invalid ago %q in %s: %w", fe.Ago, ls.Name, err)
+					}
+					ts = now.Add(-ago).UnixMilli()
+				}
+				if i == 0 || ts < first {
+					first = ts
+				}
+				if ts > last {
+					last = ts
+				}
+
+				m.events[lg.Name] = append(m.events[lg.Name], cwlTypes.FilteredLogEvent{
+					EventId:       aws.String(fmt.Sprintf("%s/%s/%d", lg.Name, ls.Name, seen+i)),
+					IngestionTime: aws.Int64(ts),
+					LogStreamName: aws.String(ls.Name),
+					Message:       aws.String(fe.Message),
+					Timestamp:     aws.Int64(ts),
+				})
+			}
+
+			i := slices.IndexFunc(m.streams[lg.Name], func(s cwlTypes.LogStream) bool {
+				return aws.ToString(s.LogStreamName) == ls.Name
+			})
+			if i < 0 {
+				m.streams[lg.Name] = append(m.streams[lg.Name], cwlTypes.LogStream{
+					LogStreamName:       aws.String(ls.Name),
+					FirstEventTimestamp: aws.Int64(first),
+					LastEventTimestamp:  aws.Int64(last),
+					LastIngestionTime:   aws.Int64(last),
+				})
+				continue
+			}
+			stream := &m.streams[lg.Name][i]
+			if len(ls.Events) > 0 {
+				if seen > 0 {
+					first = min(first, aws.ToInt64(stream.FirstEventTimestamp))
+					last = max(last, aws.ToInt64(stream.LastEventTimestamp))
+				}
+				stream.FirstEventTimestamp = aws.Int64(first)
+				stream.LastEventTimestamp = aws.Int64(last)
+				stream.LastIngestionTime = aws.Int64(last)
+			}
+		}
+
+		sort.SliceStable(m.streams[lg.Name], func(i, j int) bool {
+			return aws.ToInt64(m.streams[lg.Name][i].LastEventTimestamp) >
+				aws.ToInt64(m.streams[lg.Name][j].LastEventTimestamp)
+		})
+		sort.SliceStable(m.events[lg.Name], func(i, j int) bool {
+			return aws.ToInt64(m.events[lg.Name][i].Timestamp) <
+				aws.ToInt64(m.events[lg.Name][j].Timestamp)
+		})
+	}
+
+	sort.SliceStable(m.logGroups, func(i, j int) bool {
+		return aws.ToString(m.logGroups[i].LogGroupName) < aws.ToString(m.logGroups[j].LogGroupName)
+	})
+	return nil
+}
+
+// GetLogGroups returns the log groups whose name contains the filter pattern.
+// Like DescribeLogGroups, the match is case-insensitive.
+func (m *MemoryBackend) GetLogGroups(input *LogGroupInput) (*LogGroupOutput, error) {
+	pattern := strings.ToLower(input.FilterPattern)
+
+	var matched []cwlTypes.LogGroup
+	for _, lg := range m.logGroups {
+		if strings.Contains(strings.ToLower(aws.ToString(lg.LogGroupName)), pattern) {
+			matched = append(matched, lg)
+		}
+	}
+
+	page, next, err := paginate(matched, input.NextToken, int(limitOr(input.Limit, DefaultPageSize)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe log groups: %w", err)
+	}
+	return &LogGroupOutput{
+		LogGroups: page,
+		NextToken: next,
+	}, nil
+}
+
+// GetLogStreams returns the log streams of a log group ordered by last event time.
+func (m *MemoryBackend) GetLogStreams(input *LogStreamInput) (*LogStreamOutput, error) {
+	streams, ok := m.streams[input.LogGroupName]
+	if !ok {
+		return nil, fmt.Errorf("failed to describe log streams: log group %s does not exist", input.LogGroupName)
+	}
+
+	page, next, err := paginate(streams, input.NextToken, int(limitOr(input.Limit, DefaultPageSize)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe log streams: %w", err)
+	}
+	return &LogStreamOutput{
+		LogStreams: page,
+		NextToken:  next,
+	}, nil
+}
+
+// GetLogEvents returns a page of up to input.Limit events matching the input.
+func (m *MemoryBackend) GetLogEvents(input *LogEventInput) (*LogEventOutput, error) {
+	events, err := m.filterEvents(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe log events: %w", err)
+	}
+
+	page, next, err := paginate(events, input.NextToken, int(limitOr(input.Limit, DefaultEventsInPage)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe log events: %w", err)
+	}
+	return &LogEventOutput{
+		LogEvents: page,
+		NextToken: next,
+	}, nil
+}
+
+// WriteLogEvents writes all events matching the input to the output file,
+// one page at a time like Client.WriteLogEvents.
+func (m *MemoryBackend) WriteLogEvents(input *LogEventInput) error {
+	events, err := m.filterEvents(input)
+	if err != nil {
+		return fmt.Errorf("unable to get log events: %w", err)
+	}
+
+	return export(input, func(e *exporter) error {
+		token := e.nextToken()
+		for {
+			if input.Ctx != nil && input.Ctx.Err() != nil {
+				return fmt.Errorf("unable to get log events: %w", input.Ctx.Err())
+			}
+			page, next, err := paginate(events, token, int(limitOr(input.Limit, DefaultEventsInPage)))
+			if err != nil {
+				return fmt.Errorf("unable to get log events: %w", err)
+			}
+			if err := e.writePage(page, next); err != nil {
+				return err
+			}
+			if next == nil {
+				return nil
+			}
+			token = next
+		}
+	})
+}
+
+// StartQuery runs a query against the fixture data. The query string is not
+// interpreted: the result lists @timestamp, @log, @logStream and @message of
+// every event in the time range, newest first.
+func (m *MemoryBackend) StartQuery(input *QueryInput) (*QueryOutput, error) {
+	type groupEvent struct {
+		logGroupName string
+		event        cwlTypes.FilteredLogEvent
+	}
+
+	var events []groupEvent
+	for _, logGroupName := range input.LogGroupNames {
+		groupEvents, err := m.filterEvents(&LogEventInput{
+			LogGroupName: logGroupName,
+			StartTime:    input.StartTime,
+			EndTime:      input.EndTime,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to start query: %w", err)
+		}
+		for _, event := range groupEvents {
+			events = append(events, groupEvent{logGroupName, event})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return aws.ToInt64(events[i].event.Timestamp) > aws.ToInt64(events[j].event.Timestamp)
+	})
+	if len(events) > int(MaxQueryResults) {
+		events = events[:MaxQueryResults]
+	}
+
+	output := &QueryResultsOutput{
+		Status:         cwlTypes.QueryStatusComplete,
+		RecordsMatched: float64(len(events)),
+		RecordsScanned: float64(len(events)),
+	}
+	for _, ge := range events {
+		timestamp := time.UnixMilli(aws.ToInt64(ge.event.Timestamp)).UTC().Format("2006-01-02 15:04:05.000")
+		output.BytesScanned += float64(len(aws.ToString(ge.event.Message)))
+		output.Results = append(output.Results, []cwlTypes.ResultField{
+			{Field: aws.String("@timestamp"), Value: aws.String(timestamp)},
+			{Field: aws.String("@log"), Value: aws.String(ge.logGroupName)},
+			{Field: aws.String("@logStream"), Value: ge.event.LogStreamName},
+			{Field: aws.String("@message"), Value: ge.event.Message},
+		})
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	queryId := strconv.Itoa(len(m.queries) + 1)
+	m.queries[queryId] = output
+	return &QueryOutput{
+		QueryId: queryId,
+	}, nil
+}
+
+// GetQueryResults returns the results of a query started with StartQuery.
+func (m *MemoryBackend) GetQueryResults(input *QueryResultsInput) (*QueryResultsOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	output, ok := m.queries[input.QueryId]
+	if !ok {
+		return nil, fmt.Errorf("failed to get query results: query %s does not exist", input.QueryId)
+	}
+	return output, nil
+}
+
+// StopQuery marks a query as cancelled.
+func (m *MemoryBackend) StopQuery(input *QueryResultsInput) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	output, ok := m.queries[input.QueryId]
+	if !ok {
+		return fmt.Errorf("failed to stop query: query %s does not exist", input.QueryId)
+	}
+	output.Status = cwlTypes.QueryStatusCancelled
+	return nil
+}
+
+// filterEvents selects the events of a log group that fall into the
+// requested time range, streams and filter pattern.
+func (m *MemoryBackend) filterEvents(input *LogEventInput) ([]cwlTypes.FilteredLogEvent, error) {
+	if _, ok := m.streams[input.LogGroupName]; !ok {
+		return nil, fmt.Errorf("log group %s does not exist", input.LogGroupName)
+	}
+	all := m.events[input.LogGroupName]
+
+	start := input.StartTime.UnixMilli()
+	end := input.EndTime.UnixMilli()
+	pattern, err := ParseFilterPattern(input.FilterPattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter pattern: %w", err)
+	}
+
+	var events []cwlTypes.FilteredLogEvent
+	for _, event := range all {
+		ts := aws.ToInt64(event.Timestamp)
+		if ts < start || ts > end {
+			continue
+		}
+		if len(input.LogStreamNames) > 0 && !slices.Contains(input.LogStreamNames, aws.ToString(event.LogStreamName)) {
+			continue
+		}
+		if !pattern.Match(aws.ToString(event.Message)) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// paginate returns the page of items starting at the offset encoded in token,
+// together with the token of the following page, if any.
+func paginate[T any](items []T, token *string, size int) ([]T, *string, error) {
+	offset := 0
+	if token != nil {
+		var err error
+		offset, err = strconv.Atoi(*token)
+		if err != nil || offset < 0 || offset > len(items) {
+			return nil, nil, fmt.Errorf("invalid next token %q", *token)
+		}
+	}
+
+	end := offset + size
+	if end >= len(items) {
+		return items[offset:], nil, nil
+	}
+	return items[offset:end], aws.String(strconv.Itoa(end)), nil
+}
@@ -0,0 +1,439 @@
+package aws
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwlTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// testNow is the time the test fixtures are loaded at.
+var testNow = time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+const testGroup = "/test/app"
+
+// testFixture has seven events of testGroup in two streams, "e1" to "e7" from oldest to newest.
+var testFixture = &Fixture{LogGroups: []FixtureLogGroup{
+	{Name: testGroup, LogStreams: []FixtureLogStream{
+		{Name: "a", Events: []FixtureEvent{
+			{Ago: "50m", Message: "e1"},
+			{Ago: "30m", Message: "e3"},
+			{Ago: "20m", Message: "e4"},
+			{Ago: "5m", Message: "e7"},
+		}},
+		{Name: "b", Events: []FixtureEvent{
+			{Ago: "40m", Message: "e2"},
+			{Ago: "15m", Message: "e5"},
+			{Ago: "10m", Message: "e6"},
+		}},
+	}},
+	{Name: "/test/other"},
+	{Name: "/test/third"},
+}}
+
+func newTestBackend(t *testing.T) *MemoryBackend {
+	t.Helper()
+	m, err := NewMemoryBackend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddFixture(testFixture, testNow); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func testEventInput() *LogEventInput {
+	return &LogEventInput{
+		LogGroupName: testGroup,
+		StartTime:    testNow.Add(-time.Hour),
+		EndTime:      testNow,
+	}
+}
+
+func messages(events []cwlTypes.FilteredLogEvent) []string {
+	var messages []string
+	for _, event := range events {
+		messages = append(messages, aws.ToString(event.Message))
+	}
+	return messages
+}
+
+func TestMemoryBackendGetLogEventsPaging(t *testing.T) {
+	all := []string{"e1", "e2", "e3", "e4", "e5", "e6", "e7"}
+	tests := []struct {
+		name      string
+		limit     int32
+		streams   []string
+		filter    string
+		wantPages int
+		want      []string
+	}{
+		{name: "one page", limit: 10, wantPages: 1, want: all},
+		{name: "default page size", wantPages: 1, want: all},
+		{name: "exact pages", limit: 7, wantPages: 1, want: all},
+		{name: "single events", limit: 1, wantPages: 7, want: all},
+		{name: "last page short", limit: 3, wantPages: 3, want: all},
+		{name: "stream", limit: 2, streams: []string{"b"}, wantPages: 2, want: []string{"e2", "e5", "e6"}},
+		{name: "filter", limit: 2, filter: "?e1 ?e7", wantPages: 1, want: []string{"e1", "e7"}},
+		{name: "no events", limit: 2, filter: "nothing", wantPages: 1, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestBackend(t)
+			input := testEventInput()
+			input.Limit = tt.limit
+			input.LogStreamNames = tt.streams
+			input.FilterPattern = tt.filter
+
+			var got []string
+			pages := 0
+			for {
+				output, err := m.GetLogEvents(input)
+				if err != nil {
+					t.Fatal(err)
+				}
+				pages++
+				got = append(got, messages(output.LogEvents)...)
+				if output.NextToken == nil {
+					break
+				}
+				input.NextToken = output.NextToken
+			}
+			if pages != tt.wantPages {
+				t.Errorf("pages = %d, want %d", pages, tt.wantPages)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("events = %v, want %v", got, tt.want)
+			}
+
+			// GetAllLogEvents follows the pages itself
+			input.NextToken = nil
+			output, err := GetAllLogEvents(m, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := messages(output.LogEvents); !slices.Equal(got, tt.want) {
+				t.Errorf("GetAllLogEvents = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryBackendPagingErrors(t *testing.T) {
+	m := newTestBackend(t)
+	tests := []struct {
+		name  string
+		input *LogEventInput
+	}{
+		{"unknown log group", &LogEventInput{LogGroupName: "/test/missing"}},
+		{"invalid token", &LogEventInput{LogGroupName: testGroup, EndTime: testNow, NextToken: aws.String("x")}},
+		{"token past the end", &LogEventInput{LogGroupName: testGroup, EndTime: testNow, NextToken: aws.String("100")}},
+		{"invalid filter pattern", &LogEventInput{LogGroupName: testGroup, EndTime: testNow, FilterPattern: "{ $.a"}},
+	}
+	for _, tt := range tests {
+		if _, err := m.GetLogEvents(tt.input); err == nil {
+			t.Errorf("%s: GetLogEvents succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestMemoryBackendGetLogGroupsAndStreamsPaging(t *testing.T) {
+	m := newTestBackend(t)
+
+	var groups []string
+	input := &LogGroupInput{FilterPattern: "TEST", Limit: 2}
+	for pages := 1; ; pages++ {
+		output, err := m.GetLogGroups(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, lg := range output.LogGroups {
+			groups = append(groups, aws.ToString(lg.LogGroupName))
+		}
+		if output.NextToken == nil {
+			if pages != 2 {
+				t.Errorf("log group pages = %d, want 2", pages)
+			}
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	if want := []string{"/test/app", "/test/other", "/test/third"}; !slices.Equal(groups, want) {
+		t.Errorf("log groups = %v, want %v", groups, want)
+	}
+
+	output, err := m.GetLogStreams(&LogStreamInput{LogGroupName: testGroup, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// streams are ordered by their last event, newest first
+	if len(output.LogStreams) != 1 || aws.ToString(output.LogStreams[0].LogStreamName) != "a" || output.NextToken == nil {
+		t.Fatalf("first stream page = %+v, want stream a and a next token", output)
+	}
+	output, err = m.GetLogStreams(&LogStreamInput{LogGroupName: testGroup, Limit: 1, NextToken: output.NextToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output.LogStreams) != 1 || aws.ToString(output.LogStreams[0].LogStreamName) != "b" || output.NextToken != nil {
+		t.Fatalf("second stream page = %+v, want stream b and no next token", output)
+	}
+}
+
+func TestFollowerWithMemoryBackend(t *testing.T) {
+	tests := []struct {
+		name string
+		// batches are added to the backend one by one, each followed by a poll
+		batches [][]FixtureEvent
+		want    [][]string
+	}{
+		{
+			name:    "no new events",
+			batches: [][]FixtureEvent{nil},
+			want:    [][]string{nil},
+		},
+		{
+			name: "new events",
+			batches: [][]FixtureEvent{
+				{{Ago: "2m", Message: "n1"}, {Ago: "1m", Message: "n2"}},
+				{{Ago: "30s", Message: "n3"}},
+			},
+			want: [][]string{{"n1", "n2"}, {"n3"}},
+		},
+		{
+			name: "late event within the overlap",
+			batches: [][]FixtureEvent{
+				{{Ago: "1m", Message: "n1"}},
+				// older than n1, but within FollowOverlap of it
+				{{Ago: "1m20s", Message: "late"}},
+			},
+			want: [][]string{{"n1"}, {"late"}},
+		},
+		{
+			name: "more than a page",
+			batches: [][]FixtureEvent{
+				{{Ago: "4m", Message: "n1"}, {Ago: "3m", Message: "n2"}, {Ago: "2m", Message: "n3"},
+					{Ago: "1m", Message: "n4"}, {Ago: "30s", Message: "n5"}},
+			},
+			want: [][]string{{"n1", "n2", "n3", "n4", "n5"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestBackend(t)
+			input := testEventInput()
+			input.Limit = 2
+
+			f := NewFollower(input.StartTime)
+			poll := func() []string {
+				t.Helper()
+				input.StartTime = f.From()
+				output, err := GetAllLogEvents(m, input)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return messages(f.Unseen(testGroup, output.LogEvents))
+			}
+
+			if got, want := poll(), []string{"e1", "e2", "e3", "e4", "e5", "e6", "e7"}; !slices.Equal(got, want) {
+				t.Fatalf("first poll = %v, want %v", got, want)
+			}
+			for i, batch := range tt.batches {
+				// new events of stream a, like a process still writing to it
+				stream := FixtureLogStream{Name: "a", Events: batch}
+				fixture := &Fixture{LogGroups: []FixtureLogGroup{{Name: testGroup, LogStreams: []FixtureLogStream{stream}}}}
+				if err := m.AddFixture(fixture, testNow); err != nil {
+					t.Fatal(err)
+				}
+				if got := poll(); !slices.Equal(got, tt.want[i]) {
+					t.Errorf("poll after batch %d = %v, want %v", i+1, got, tt.want[i])
+				}
+			}
+			if got := poll(); got != nil {
+				t.Errorf("last poll = %v, want no events", got)
+			}
+		})
+	}
+}
+
+func TestNewMemoryBackendStreamInSeveralFixtures(t *testing.T) {
+	dir := t.TempDir()
+	fixtures := []string{
+		`{"logGroups": [{"name": "/test/app", "logStreams": [{"name": "a", "events": [
+			{"timestamp": 1000, "message": "e1"}, {"timestamp": 3000, "message": "e3"}]}]}]}`,
+		`{"logGroups": [{"name": "/test/app", "logStreams": [{"name": "a", "events": [
+			{"timestamp": 2000, "message": "e2"}, {"timestamp": 4000, "message": "e4"}]}]}]}`,
+	}
+	for i, fixture := range fixtures {
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("%d.json", i)), []byte(fixture), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, err := NewMemoryBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	streams, err := m.GetLogStreams(&LogStreamInput{LogGroupName: testGroup})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streams.LogStreams) != 1 {
+		t.Fatalf("got %d log streams, want stream a once", len(streams.LogStreams))
+	}
+	stream := streams.LogStreams[0]
+	if first, last := aws.ToInt64(stream.FirstEventTimestamp), aws.ToInt64(stream.LastEventTimestamp); first != 1000 || last != 4000 {
+		t.Errorf("stream a spans %d to %d, want 1000 to 4000", first, last)
+	}
+
+	output, err := m.GetLogEvents(&LogEventInput{LogGroupName: testGroup, EndTime: time.UnixMilli(5000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := messages(output.LogEvents), []string{"e1", "e2", "e3", "e4"}; !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	// the follow dedup drops events by ID, so none may share one
+	f := NewFollower(time.UnixMilli(0))
+	if got := f.Unseen(testGroup, output.LogEvents); len(got) != 4 {
+		t.Errorf("%d of 4 events unseen, event IDs are not unique", len(got))
+	}
+}
+
+func TestFollowerUnseenByLogGroup(t *testing.T) {
+	f := NewFollower(testNow)
+	event := cwlTypes.FilteredLogEvent{
+		EventId:   aws.String("1"),
+		Timestamp: aws.Int64(testNow.UnixMilli()),
+		Message:   aws.String("m"),
+	}
+	events := []cwlTypes.FilteredLogEvent{event}
+
+	tests := []struct {
+		group string
+		want  int
+	}{
+		{"/a", 1},
+		{"/a", 0},
+		// event IDs are only unique within a log group
+		{"/b", 1},
+		{"/b", 0},
+	}
+	for i, tt := range tests {
+		if got := len(f.Unseen(tt.group, events)); got != tt.want {
+			t.Errorf("poll %d of %s: %d unseen events, want %d", i+1, tt.group, got, tt.want)
+		}
+	}
+
+	// polls never start before the followed time range
+	if got := f.From(); !got.Equal(testNow) {
+		t.Errorf("From() = %v, want %v", got, testNow)
+	}
+	f.Unseen("/a", []cwlTypes.FilteredLogEvent{{
+		EventId:   aws.String("2"),
+		Timestamp: aws.Int64(testNow.Add(time.Minute).UnixMilli()),
+	}})
+	if got, want := f.From(), testNow.Add(time.Minute-FollowOverlap); !got.Equal(want) {
+		t.Errorf("From() = %v, want %v", got, want)
+	}
+}
+
+// readExport returns the contents of an exported file, decompressed if it is gzipped.
+func readExport(t *testing.T, path string, gzipped bool) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gzipped {
+		return string(data)
+	}
+	// a resumed export consists of several gzip members
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMemoryBackendWriteLogEventsResume(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		format OutputFormat
+		gzip   bool
+		// stopAfter is the number of pages written before the export is cancelled
+		stopAfter int
+	}{
+		{name: "raw", file: "out.txt", format: FormatRaw, stopAfter: 1},
+		{name: "jsonl", file: "out.jsonl", format: FormatAuto, stopAfter: 2},
+		{name: "csv header once", file: "out.csv", format: FormatCSV, stopAfter: 2},
+		{name: "text", file: "out.log", format: FormatText, stopAfter: 3},
+		{name: "gzip", file: "out.jsonl.gz", format: FormatJSONL, gzip: true, stopAfter: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestBackend(t)
+			dir := t.TempDir()
+
+			full := testEventInput()
+			full.OutputFile = filepath.Join(dir, "full-"+tt.file)
+			full.OutputFormat = tt.format
+			full.Gzip = tt.gzip
+			full.Limit = 2
+			if err := m.WriteLogEvents(full); err != nil {
+				t.Fatal(err)
+			}
+			want := readExport(t, full.OutputFile, tt.gzip)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var progress ExportProgress
+			input := *full
+			input.OutputFile = filepath.Join(dir, tt.file)
+			input.Ctx = ctx
+			input.OnProgress = func(p ExportProgress) {
+				progress = p
+				if p.Pages == tt.stopAfter {
+					cancel()
+				}
+			}
+			if err := m.WriteLogEvents(&input); !errors.Is(err, context.Canceled) {
+				t.Fatalf("cancelled export error = %v, want context.Canceled", err)
+			}
+			if progress.Pages != tt.stopAfter || progress.Events != 2*tt.stopAfter || progress.NextToken == nil {
+				t.Fatalf("cancelled export progress = %+v, want %d pages and a next token", progress, tt.stopAfter)
+			}
+			if info, err := os.Stat(input.OutputFile); err != nil || info.Size() != progress.Bytes {
+				t.Fatalf("cancelled export file size = %v (%v), want %d bytes", info.Size(), err, progress.Bytes)
+			}
+
+			resumed := progress
+			input.Ctx = context.Background()
+			input.Resume = &resumed
+			if err := m.WriteLogEvents(&input); err != nil {
+				t.Fatal(err)
+			}
+			if progress.Events != 7 || progress.Pages != 4 || progress.NextToken != nil {
+				t.Errorf("resumed export progress = %+v, want 7 events in 4 pages", progress)
+			}
+			if got := readExport(t, input.OutputFile, tt.gzip); got != want {
+				t.Errorf("resumed export =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Package aws provides AWS CloudWatch Logs client functionality for the TUI application.
+package aws
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// Regions lists commonly used AWS regions offered by the region picker.
+var Regions = []string{
+	"us-east-1",
+	"us-east-2",
+	"us-west-1",
+	"us-west-2",
+	"ca-central-1",
+	"sa-east-1",
+	"eu-west-1",
+	"eu-west-2",
+	"eu-west-3",
+	"eu-central-1",
+	"eu-north-1",
+	"ap-northeast-1",
+	"ap-northeast-2",
+	"ap-northeast-3",
+	"ap-southeast-1",
+	"ap-southeast-2",
+	"ap-south-1",
+}
+
+// ListProfiles returns the names of the profiles defined in the shared
+// config and credentials files, sorted and without duplicates.
+// The AWS_CONFIG_FILE and AWS_SHARED_CREDENTIALS_FILE variables are honored.
+func ListProfiles() ([]string, error) {
+	configFile := os.Getenv("AWS_CONFIG_FILE")
+	if configFile == "" {
+		configFile = config.DefaultSharedConfigFilename()
+	}
+	credentialsFile := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = config.DefaultSharedCredentialsFilename()
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range []struct {
+		path     string
+		isConfig bool
+	}{
+		{configFile, true},
+		{credentialsFile, false},
+	} {
+		names, err := readProfileNames(file.path, file.isConfig)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			seen[name] = true
+		}
+	}
+
+	profiles := make([]string, 0, len(seen))
+	for name := range seen {
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// readProfileNames returns the profile sections of a shared config or credentials file.
+// In the config file, profiles are written as [profile NAME] except for [default].
+// A missing file yields no profiles.
+func readProfileNames(path string, isConfig bool) ([]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+			continue
+		}
+		section := strings.TrimSpace(line[1 : len(line)-1])
+
+		if !isConfig || section == "default" {
+			names = append(names, section)
+			continue
+		}
+		// other sections such as [sso-session NAME] are not profiles
+		if name, ok := strings.CutPrefix(section, "profile "); ok {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	return names, nil
+}
@@ -0,0 +1,216 @@
+// Package config provides configuration management for the CloudWatch Log TUI application.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AppName is the name of the application directory under the user config directory.
+const AppName = "cloudwatch-log-tui"
+
+// BookmarksFile is the name of the file bookmarks are stored in.
+const BookmarksFile = "bookmarks.json"
+
+// Dir returns the directory the application stores its files in:
+// $XDG_CONFIG_HOME/cloudwatch-log-tui, or ~/.config/cloudwatch-log-tui.
+func Dir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, AppName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find the home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", AppName), nil
+}
+
+// Bookmark is a named log event view: the log group, streams, filter,
+// time range and output format to open it with.
+type Bookmark struct {
+	Name          string   `json:"name"`
+	LogGroupName  string   `json:"logGroupName"`
+	LogGroupNames []string `json:"logGroupNames,omitempty"`
+	LogStreams    []string `json:"logStreams,omitempty"`
+	FilterPattern string   `json:"filterPattern,omitempty"`
+	// Relative is the duration of a relative time range like "15m" or "7d", empty for an absolute range
+	Relative     string    `json:"relative,omitempty"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	OutputFormat string    `json:"outputFormat,omitempty"`
+}
+
+// Groups returns the log groups of the bookmark. LogGroupNames is only set
+// for a view merging several log groups, LogGroupName being the first of them.
+func (b Bookmark) Groups() []string {
+	if len(b.LogGroupNames) > 0 {
+		return slices.Clone(b.LogGroupNames)
+	}
+	if b.LogGroupName == "" {
+		return nil
+	}
+	return []string{b.LogGroupName}
+}
+
+// TimeRange describes the time range of the bookmark for display.
+func (b Bookmark) TimeRange() string {
+	if b.Relative != "" {
+		return "last " + b.Relative
+	}
+	return b.Start.Local().Format("2006-01-02 15:04") + ".." + b.End.Local().Format("2006-01-02 15:04")
+}
+
+// Bookmarks stores bookmarks in a JSON file, keeping them sorted by name.
+type Bookmarks struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewBookmarks returns the bookmark store in the application directory.
+func NewBookmarks() (*Bookmarks, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &Bookmarks{path: filepath.Join(dir, BookmarksFile)}, nil
+}
+
+// Path returns the file the bookmarks are stored in.
+func (s *Bookmarks) Path() string {
+	return s.path
+}
+
+// List returns all bookmarks. A missing file means there are no bookmarks yet.
+func (s *Bookmarks) List() ([]Bookmark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+// Get returns the bookmark with the given name.
+func (s *Bookmarks) Get(name string) (Bookmark, error) {
+	bookmarks, err := s.List()
+	if err != nil {
+		return Bookmark{}, err
+	}
+	i := slices.IndexFunc(bookmarks, func(b Bookmark) bool { return b.Name == name })
+	if i < 0 {
+		return Bookmark{}, fmt.Errorf("bookmark %q does not exist", name)
+	}
+	return bookmarks[i], nil
+}
+
+// Put adds the bookmark, replacing an existing bookmark with the same name.
+func (s *Bookmarks) Put(bookmark Bookmark) error {
+	bookmark.Name = strings.TrimSpace(bookmark.Name)
+	if bookmark.Name == "" {
+		return errors.New("bookmark name must not be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bookmarks, err := s.load()
+	if err != nil {
+		return err
+	}
+	bookmarks = slices.DeleteFunc(bookmarks, func(b Bookmark) bool { return b.Name == bookmark.Name })
+	return s.save(append(bookmarks, bookmark))
+}
+
+// Rename changes the name of a bookmark. The new name must not be taken.
+func (s *Bookmarks) Rename(oldName string, newName string) error {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return errors.New("bookmark name must not be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bookmarks, err := s.load()
+	if err != nil {
+		return err
+	}
+	if newName != oldName && slices.ContainsFunc(bookmarks, func(b Bookmark) bool { return b.Name == newName }) {
+		return fmt.Errorf("bookmark %q already exists", newName)
+	}
+	i := slices.IndexFunc(bookmarks, func(b Bookmark) bool { return b.Name == oldName })
+	if i < 0 {
+		return fmt.Errorf("bookmark %q does not exist", oldName)
+	}
+	bookmarks[i].Name = newName
+	return s.save(bookmarks)
+}
+
+// Delete removes the bookmark with the given name.
+func (s *Bookmarks) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bookmarks, err := s.load()
+	if err != nil {
+		return err
+	}
+	n := len(bookmarks)
+	bookmarks = slices.DeleteFunc(bookmarks, func(b Bookmark) bool { return b.Name == name })
+	if len(bookmarks) == n {
+		return fmt.Errorf("bookmark %q does not exist", name)
+	}
+	return s.save(bookmarks)
+}
+
+// load reads the bookmarks file. The caller must hold the lock.
+func (s *Bookmarks) load() ([]Bookmark, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read bookmarks: %w", err)
+	}
+
+	var bookmarks []Bookmark
+	if err := json.Unmarshal(data, &bookmarks); err != nil {
+		return nil, fmt.Errorf("unable to parse bookmarks %s: %w", s.path, err)
+	}
+	return bookmarks, nil
+}
+
+// save writes the bookmarks file, replacing it atomically. The caller must hold the lock.
+func (s *Bookmarks) save(bookmarks []Bookmark) error {
+	slices.SortFunc(bookmarks, func(a, b Bookmark) int { return strings.Compare(a.Name, b.Name) })
+
+	data, err := json.MarshalIndent(bookmarks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode bookmarks: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("unable to create %s: %w", filepath.Dir(s.path), err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), BookmarksFile+".*")
+	if err != nil {
+		return fmt.Errorf("unable to write bookmarks: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write bookmarks: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write bookmarks: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("unable to write bookmarks: %w", err)
+	}
+	return nil
+}
@@ -3,28 +3,393 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
-	// "path/filepath"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+	"gopkg.in/yaml.v3"
 )
 
+// ConfigFile is the name of the configuration file in the application directory.
+const ConfigFile = "config.yaml"
+
+// EnvPrefix is the prefix of the environment variables overriding settings,
+// e.g. CLOUDWATCH_LOG_TUI_PAGE_SIZE.
+const EnvPrefix = "CLOUDWATCH_LOG_TUI_"
+
+// DefaultMaxEventsPerView is the most log events kept in the log viewer by default.
+const DefaultMaxEventsPerView int32 = 50000
+
 // Config holds the application configuration
 type Config struct {
-	LogFile string
+	// Profile and Region select the AWS credentials; empty means the AWS SDK defaults
+	Profile     string `yaml:"profile"`
+	Region      string `yaml:"region"`
+	EndpointURL string `yaml:"endpoint_url"`
+	// TimeRange is the relative time range log events are shown for, e.g. "15m", "1h" or "7d"
+	TimeRange string `yaml:"time_range"`
+	// PageSize is the number of log groups and streams listed per page
+	PageSize int32 `yaml:"page_size"`
+	// MaxEvents is the number of log events fetched per page of the log viewer
+	MaxEvents int32 `yaml:"max_events"`
+	// MaxEventsPerView is the most log events kept in the log viewer, the oldest are dropped beyond it
+	MaxEventsPerView int32 `yaml:"max_events_per_view"`
+	// Timezone is the time zone timestamps and time ranges are shown in: "Local", "UTC" or an IANA name
+	Timezone string       `yaml:"timezone"`
+	Theme    string       `yaml:"theme"`
+	Export   ExportConfig `yaml:"export"`
+	LogFile  string       `yaml:"log_file"`
+	// Keys replaces the default keys of actions, e.g. search: [/, Ctrl-S]
+	Keys map[string]KeyList `yaml:"keys,omitempty"`
+
+	// path is the file the configuration was loaded from, empty if none was found
+	path string
+}
+
+// ExportConfig holds the defaults for saving log events.
+type ExportConfig struct {
+	Format string `yaml:"format"`
+	File   string `yaml:"file"`
+}
+
+// KeyList is the list of keys bound to an action.
+// A single key can be written without a list.
+type KeyList []string
+
+// UnmarshalYAML decodes a key or a list of keys.
+func (l *KeyList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = KeyList{node.Value}
+		return nil
+	}
+	var keys []string
+	if err := node.Decode(&keys); err != nil {
+		return err
+	}
+	*l = keys
+	return nil
 }
 
 // New creates a new configuration with default values
 func New() *Config {
-	// homeDir, err := os.UserHomeDir()
-	// if err != nil {
-	// 	homeDir = "."
-	// }
-
 	return &Config{
-		LogFile: "cloudwatch-log-tui.log",
+		TimeRange:        "1h",
+		PageSize:         awsr.DefaultPageSize,
+		MaxEvents:        awsr.DefaultEventsInPage,
+		MaxEventsPerView: DefaultMaxEventsPerView,
+		Timezone:         "Local",
+		Theme:            view.DefaultTheme,
+		Export: ExportConfig{
+			Format: string(awsr.FormatAuto),
+		},
+		LogFile: defaultLogFile(),
+	}
+}
+
+// defaultLogFile returns $XDG_STATE_HOME/cloudwatch-log-tui/cloudwatch-log-tui.log,
+// or ~/.local/state/cloudwatch-log-tui/cloudwatch-log-tui.log.
+// It falls back to the current directory if there is no home directory.
+func defaultLogFile() string {
+	name := AppName + ".log"
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, AppName, name)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return name
 	}
+	return filepath.Join(home, ".local", "state", AppName, name)
 }
 
-// InitLogging initializes the application logging
+// DefaultPath returns the configuration file in the application directory.
+func DefaultPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ConfigFile), nil
+}
+
+// Load reads the configuration file at path over the defaults and applies the
+// environment variable overrides. If path is empty, the file in the application
+// directory is read if it exists. Unknown keys are reported as errors.
+// The result is not validated, see Validate.
+func Load(path string) (*Config, error) {
+	c := New()
+
+	explicit := path != ""
+	if !explicit {
+		p, err := DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = p
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && !explicit:
+		// no configuration file, keep the defaults
+	case err != nil:
+		return nil, fmt.Errorf("unable to read config: %w", err)
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("unable to parse config %s: %w", path, err)
+		}
+		c.path = path
+	}
+
+	if err := c.applyEnv(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Path returns the file the configuration was loaded from, or an empty string
+// if the defaults are used.
+func (c *Config) Path() string {
+	return c.path
+}
+
+// setting describes a setting that can be overridden by an environment variable and a flag.
+type setting struct {
+	key   string
+	usage string
+	set   func(c *Config, value string) error
+}
+
+// Env returns the environment variable overriding the setting.
+func (s setting) Env() string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(s.key))
+}
+
+// Flag returns the command line flag overriding the setting.
+func (s setting) Flag() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(s.key)
+}
+
+// settings lists the settings in the order of the configuration file.
+var settings = []setting{
+	{"profile", "AWS profile to use (default: AWS_PROFILE or the default profile)",
+		func(c *Config, v string) error { c.Profile = v; return nil }},
+	{"region", "AWS region to use (default: AWS_REGION or the profile's region)",
+		func(c *Config, v string) error { c.Region = v; return nil }},
+	{"endpoint_url", "CloudWatch Logs endpoint, e.g. http://localhost:4566 for LocalStack (default: " + awsr.EndpointURLEnv + ")",
+		func(c *Config, v string) error { c.EndpointURL = v; return nil }},
+	{"time_range", "relative time range log events are shown for, e.g. 15m, 1h or 7d",
+		func(c *Config, v string) error { c.TimeRange = v; return nil }},
+	{"page_size", "number of log groups and streams listed per page",
+		func(c *Config, v string) error { return setInt32(&c.PageSize, v) }},
+	{"max_events", "number of log events fetched per page of the log viewer",
+		func(c *Config, v string) error { return setInt32(&c.MaxEvents, v) }},
+	{"max_events_per_view", "most log events kept in the log viewer while paging and following, the oldest are dropped",
+		func(c *Config, v string) error { return setInt32(&c.MaxEventsPerView, v) }},
+	{"timezone", `time zone of timestamps and time ranges: "Local", "UTC" or an IANA name like Asia/Tokyo`,
+		func(c *Config, v string) error { c.Timezone = v; return nil }},
+	{"theme", "color theme: " + strings.Join(view.ThemeNames(), ", "),
+		func(c *Config, v string) error { c.Theme = v; return nil }},
+	{"export.format", "default format for saving log events: auto, raw, jsonl, csv or text",
+		func(c *Config, v string) error { c.Export.Format = v; return nil }},
+	{"export.file", "default file for saving log events",
+		func(c *Config, v string) error { c.Export.File = v; return nil }},
+	{"log_file", "file the application log is written to",
+		func(c *Config, v string) error { c.LogFile = v; return nil }},
+}
+
+// setInt32 parses a number setting.
+func setInt32(field *int32, value string) error {
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", value)
+	}
+	*field = int32(n)
+	return nil
+}
+
+// applyEnv overrides settings from environment variables.
+// AWS_PROFILE and AWS_REGION override the profile and region of the configuration file,
+// the CLOUDWATCH_LOG_TUI_ variables override everything.
+func (c *Config) applyEnv() error {
+	if v := os.Getenv("AWS_PROFILE"); v != "" {
+		c.Profile = v
+	}
+	if v := os.Getenv("AWS_REGION"); v != "" {
+		c.Region = v
+	}
+	for _, s := range settings {
+		v, ok := os.LookupEnv(s.Env())
+		if !ok {
+			continue
+		}
+		if err := s.set(c, v); err != nil {
+			return fmt.Errorf("invalid %s: %w", s.Env(), err)
+		}
+	}
+	return nil
+}
+
+// RegisterFlags defines a flag for each setting on the flag set.
+// The returned function applies the flags given on the command line to a configuration,
+// after the flag set is parsed.
+func RegisterFlags(fs *flag.FlagSet) func(c *Config) error {
+	values := make(map[string]*string, len(settings))
+	for _, s := range settings {
+		values[s.Flag()] = fs.String(s.Flag(), "", s.usage)
+	}
+
+	return func(c *Config) error {
+		var err error
+		fs.Visit(func(f *flag.Flag) {
+			for _, s := range settings {
+				if s.Flag() != f.Name || err != nil {
+					continue
+				}
+				if e := s.set(c, *values[f.Name]); e != nil {
+					err = fmt.Errorf("invalid -%s: %w", f.Name, e)
+				}
+			}
+		})
+		return err
+	}
+}
+
+// Validate checks every setting and reports all invalid ones.
+func (c *Config) Validate() error {
+	var errs []error
+	if _, err := c.RelativeTime(); err != nil {
+		errs = append(errs, fmt.Errorf("time_range: %w", err))
+	}
+	if c.PageSize < 1 || c.PageSize > awsr.DefaultPageSize {
+		errs = append(errs, fmt.Errorf("page_size: must be between 1 and %d, got %d", awsr.DefaultPageSize, c.PageSize))
+	}
+	if c.MaxEvents < 1 || c.MaxEvents > awsr.MaxEventsInPage {
+		errs = append(errs, fmt.Errorf("max_events: must be between 1 and %d, got %d", awsr.MaxEventsInPage, c.MaxEvents))
+	}
+	if c.MaxEventsPerView < c.MaxEvents {
+		errs = append(errs, fmt.Errorf("max_events_per_view: must be at least max_events (%d) to hold a page, got %d", c.MaxEvents, c.MaxEventsPerView))
+	}
+	if _, err := c.Location(); err != nil {
+		errs = append(errs, fmt.Errorf("timezone: %w", err))
+	}
+	if _, ok := view.Themes[c.Theme]; !ok {
+		errs = append(errs, fmt.Errorf("theme: unknown theme %q, available themes are %s", c.Theme, strings.Join(view.ThemeNames(), ", ")))
+	}
+	if _, err := awsr.ParseOutputFormat(c.Export.Format); err != nil {
+		errs = append(errs, fmt.Errorf("export.format: %w", err))
+	}
+	if c.LogFile == "" {
+		errs = append(errs, errors.New("log_file: must not be empty"))
+	}
+	if _, err := c.Keymap(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Keymap returns the default keymap with the keys of the configuration applied.
+func (c *Config) Keymap() (*view.Keymap, error) {
+	overrides := make(map[string][]string, len(c.Keys))
+	for action, keys := range c.Keys {
+		overrides[action] = keys
+	}
+	return view.NewKeymap(overrides)
+}
+
+// RelativeTime returns the duration of the default time range.
+func (c *Config) RelativeTime() (time.Duration, error) {
+	d, err := ParseRelative(c.TimeRange)
+	if err != nil {
+		return 0, fmt.Errorf("invalid relative time range %q, use e.g. 15m, 1h or 7d", c.TimeRange)
+	}
+	return d, nil
+}
+
+// ParseRelative parses the duration of a relative time range like "-30m", "2h" or "7d".
+// The sign is optional; the duration always points into the past.
+// Time ranges are read with it throughout the app, so the configuration accepts the same ones.
+func ParseRelative(text string) (time.Duration, error) {
+	d, err := ParseDuration(strings.TrimPrefix(strings.TrimSpace(text), "-"))
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid time range %q", text)
+	}
+	return d, nil
+}
+
+// ParseDuration parses a duration, additionally accepting a "d" suffix for days.
+func ParseDuration(text string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(text, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time range %q", text)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time range %q", text)
+	}
+	return d, nil
+}
+
+// Location returns the time zone timestamps and time ranges are shown in.
+func (c *Config) Location() (*time.Location, error) {
+	switch c.Timezone {
+	case "", "Local", "local":
+		return time.Local, nil
+	case "UTC", "utc":
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", c.Timezone)
+	}
+	return loc, nil
+}
+
+// InitLogging initializes the application logging.
+// The directory of the log file is created if it does not exist.
 func (c *Config) InitLogging() (*os.File, error) {
-	return os.OpenFile(c.LogFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o666)
+	path := expandHome(c.LogFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+}
+
+// expandHome replaces a leading ~ in path with the home directory.
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && rest[0] != '/' && rest[0] != filepath.Separator) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
+
+// Print writes the effective configuration in the configuration file format.
+func (c *Config) Print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return enc.Close()
 }
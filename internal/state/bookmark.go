@@ -0,0 +1,113 @@
+// Package state manages the application state for the CloudWatch Log TUI.
+package state
+
+import (
+	"sync"
+
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
+)
+
+// BookmarkEdit is what the name input of the bookmarks page is used for.
+type BookmarkEdit int
+
+const (
+	// BookmarkBrowse means no name is being edited
+	BookmarkBrowse BookmarkEdit = iota
+	// BookmarkAdd names a new bookmark of the current log event view
+	BookmarkAdd
+	// BookmarkRename renames an existing bookmark
+	BookmarkRename
+)
+
+// Bookmark holds the state of the bookmarks page: the listed bookmarks,
+// the name being edited and the page to return to.
+type Bookmark struct {
+	bookmarks     []config.Bookmark
+	edit          BookmarkEdit
+	renaming      string
+	pendingDelete string
+	returnPage    view.Page
+	mu            sync.RWMutex
+}
+
+// SetBookmarks replaces the listed bookmarks.
+func (b *Bookmark) SetBookmarks(bookmarks []config.Bookmark) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bookmarks = bookmarks
+	b.pendingDelete = ""
+}
+
+// GetBookmarks returns the listed bookmarks.
+func (b *Bookmark) GetBookmarks() []config.Bookmark {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.bookmarks
+}
+
+// GetBookmark returns the i-th listed bookmark.
+func (b *Bookmark) GetBookmark(i int) (config.Bookmark, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if i < 0 || i >= len(b.bookmarks) {
+		return config.Bookmark{}, false
+	}
+	return b.bookmarks[i], true
+}
+
+// StartEdit starts adding a bookmark, or renaming the named one.
+func (b *Bookmark) StartEdit(edit BookmarkEdit, renaming string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.edit = edit
+	b.renaming = renaming
+	b.pendingDelete = ""
+}
+
+// GetEdit returns what the name input is used for and the bookmark being renamed.
+func (b *Bookmark) GetEdit() (BookmarkEdit, string) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.edit, b.renaming
+}
+
+// EndEdit goes back to browsing the bookmarks.
+func (b *Bookmark) EndEdit() {
+	b.StartEdit(BookmarkBrowse, "")
+}
+
+// ConfirmDelete returns true if the named bookmark was already asked to be deleted,
+// otherwise it remembers the request so that it is confirmed by asking again.
+func (b *Bookmark) ConfirmDelete(name string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pendingDelete == name {
+		b.pendingDelete = ""
+		return true
+	}
+	b.pendingDelete = name
+	return false
+}
+
+// SetReturnPage sets the page shown when the bookmarks page is closed.
+func (b *Bookmark) SetReturnPage(page view.Page) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.returnPage = page
+}
+
+// GetReturnPage returns the page shown when the bookmarks page is closed.
+func (b *Bookmark) GetReturnPage() view.Page {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.returnPage
+}
@@ -2,14 +2,20 @@
 package state
 
 import (
+	"cmp"
 	"fmt"
-	"log"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwlTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/rivo/tview"
 	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
 	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
 )
 
@@ -26,22 +32,64 @@ type LogEvent struct {
 	endDay             int
 	endHour            int
 	endMinute          int
-	logGroupName       string
+	logGroupNames      []string
 	logStreamNames     []string
 	filterPatern       string
 	enableFilterPatern bool
 	outputFile         string
 	enableOutputFile   bool
+	outputFormat       awsr.OutputFormat
+	relative           time.Duration
+	following          bool
+	followGeneration   int
+	follower           *awsr.Follower
+	pageInput          awsr.LogEventInput
+	pageGroups         []string
+	hasNext            bool
+	nextTokens         map[string]*string
+	loadedEvents       int
+	droppedEvents      int
+	maxEventsInView    int
+	events             []cwlTypes.FilteredLogEvent
+	eventGroups        []string
+	messageMode        view.MessageMode
+	tableView          bool
+	visual             bool
+	visualAnchor       int
+	utc                bool
+	searchQuery        string
+	searchRegex        bool
+	search             *regexp.Regexp
+	searchMatch        int
+	exportProgress     awsr.ExportProgress
+	exportResume       *awsr.LogEventInput
+	exportRelative     time.Duration
+	eventsInPage       int32
+	defaultRelative    time.Duration
 	mu                 sync.RWMutex
 }
 
 // SetLogGroupSelected updates the currently selected log group name
 // from which log events will be fetched.
 func (l *LogEvent) SetLogGroupSelected(logGroupName string) {
+	l.SetLogGroupsSelected([]string{logGroupName})
+}
+
+// SetLogGroupsSelected updates the log groups from which log events will be fetched.
+// The events of several log groups are merged in time order.
+func (l *LogEvent) SetLogGroupsSelected(logGroupNames []string) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	l.logGroupName = logGroupName
+	l.logGroupNames = slices.Clone(logGroupNames)
+}
+
+// GetLogGroupsSelected returns the log groups from which log events are fetched.
+func (l *LogEvent) GetLogGroupsSelected() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return slices.Clone(l.logGroupNames)
 }
 
 // GetLogStreamsSelected returns the list of currently selected log stream names.
@@ -87,12 +135,14 @@ func (l *LogEvent) GetCurrntState() LogEvent {
 		endDay:             l.endDay,
 		endHour:            l.endHour,
 		endMinute:          l.endMinute,
-		logGroupName:       l.logGroupName,
+		logGroupNames:      l.logGroupNames,
 		logStreamNames:     l.logStreamNames,
 		filterPatern:       l.filterPatern,
 		enableFilterPatern: l.enableFilterPatern,
 		outputFile:         l.outputFile,
 		enableOutputFile:   l.enableOutputFile,
+		outputFormat:       l.outputFormat,
+		relative:           l.relative,
 	}
 }
 
@@ -114,6 +164,94 @@ func (l *LogEvent) SetFilterPatern(filterPatern string) {
 	l.filterPatern = filterPatern
 }
 
+// GetFilterPatern returns the filter pattern used to search log events.
+func (l *LogEvent) GetFilterPatern() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.filterPatern
+}
+
+// ToBookmark returns a bookmark of the log group, streams, filter pattern,
+// time range and output format currently selected.
+func (l *LogEvent) ToBookmark(name string) config.Bookmark {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	b := config.Bookmark{
+		Name:          name,
+		LogStreams:    slices.Clone(l.logStreamNames),
+		FilterPattern: l.filterPatern,
+		OutputFormat:  string(l.outputFormat),
+	}
+	if len(l.logGroupNames) > 0 {
+		b.LogGroupName = l.logGroupNames[0]
+	}
+	if len(l.logGroupNames) > 1 {
+		b.LogGroupNames = slices.Clone(l.logGroupNames)
+	}
+	if l.relative > 0 {
+		b.Relative = FormatRelative(l.relative)
+	} else {
+		b.Start = time.Date(l.startYear, time.Month(l.startMonth), l.startDay, l.startHour, l.startMinute, 0, 0, time.Local)
+		b.End = time.Date(l.endYear, time.Month(l.endMonth), l.endDay, l.endHour, l.endMinute, 0, 0, time.Local)
+	}
+	return b
+}
+
+// ApplyBookmark selects the log group, streams, filter pattern,
+// time range and output format of the bookmark.
+func (l *LogEvent) ApplyBookmark(b config.Bookmark) error {
+	groups := b.Groups()
+	if len(groups) == 0 {
+		return fmt.Errorf("bookmark %q has no log group", b.Name)
+	}
+	var relative time.Duration
+	if b.Relative != "" {
+		d, err := config.ParseRelative(b.Relative)
+		if err != nil {
+			return fmt.Errorf("bookmark %q has an invalid time range: %w", b.Name, err)
+		}
+		relative = d
+	} else if !b.Start.Before(b.End) {
+		return fmt.Errorf("bookmark %q has an invalid time range", b.Name)
+	}
+	format := awsr.FormatAuto
+	if b.OutputFormat != "" {
+		f, err := awsr.ParseOutputFormat(b.OutputFormat)
+		if err != nil {
+			return fmt.Errorf("bookmark %q: %w", b.Name, err)
+		}
+		format = f
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.logGroupNames = groups
+	l.logStreamNames = slices.Clone(b.LogStreams)
+	if l.logStreamNames == nil {
+		l.logStreamNames = []string{}
+	}
+	l.filterPatern = b.FilterPattern
+	l.outputFormat = format
+	l.relative = relative
+	if relative > 0 {
+		l.setRange(time.Now().Add(-relative), time.Now())
+	} else {
+		l.setRange(b.Start, b.End)
+	}
+	return nil
+}
+
+// GetOutputFormat returns the format log events are saved in.
+func (l *LogEvent) GetOutputFormat() awsr.OutputFormat {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.outputFormat
+}
+
 // SetOutputFile sets the output file path where log events will be saved.
 func (l *LogEvent) SetOutputFile(outputFile string) {
 	l.mu.Lock()
@@ -122,128 +260,752 @@ func (l *LogEvent) SetOutputFile(outputFile string) {
 	l.outputFile = outputFile
 }
 
-// SetDefaultTime sets the time range to default values:
-// start time is one hour before current time, end time is current time.
+// SetOutputFormat sets the format log events are saved in, by its name.
+func (l *LogEvent) SetOutputFormat(name string) error {
+	format, err := awsr.ParseOutputFormat(name)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.outputFormat = format
+	return nil
+}
+
+// SetDefaultTime sets the time range to the configured relative time range up to now.
 func (l *LogEvent) SetDefaultTime() {
+	l.SetRelativeTime(l.defaultRelative)
+}
+
+// SetRelativeTime sets the time range to the last d up to now.
+// The range is recomputed each time log events are fetched.
+func (l *LogEvent) SetRelativeTime(d time.Duration) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	now := time.Now()
-	oneHourBefore := now.Add(-1 * time.Hour)
+	l.relative = d
+	l.setRange(time.Now().Add(-d), time.Now())
+}
 
-	l.startYear = oneHourBefore.Year()
-	l.startMonth = int(oneHourBefore.Month())
-	l.startDay = oneHourBefore.Day()
-	l.startHour = oneHourBefore.Hour()
-	l.startMinute = oneHourBefore.Minute()
+// SetTimeRange sets the time range from a parsed time range expression.
+func (l *LogEvent) SetTimeRange(tr TimeRange) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	l.endYear = now.Year()
-	l.endMonth = int(now.Month())
-	l.endDay = now.Day()
-	l.endHour = now.Hour()
-	l.endMinute = now.Minute()
+	l.relative = tr.Relative
+	l.setRange(tr.Start, tr.End)
 }
 
-// BeforeGet prepares the input parameters before fetching log events.
-// It validates the state and sets all necessary query parameters.
-func (l *LogEvent) BeforeGet(input *awsr.LogEventInput) {
+// SetCustomTime keeps the current time range as an absolute range.
+func (l *LogEvent) SetCustomTime() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.relative = 0
+}
+
+// GetRelativeTime returns the duration of a relative time range,
+// or zero if the time range is absolute.
+func (l *LogEvent) GetRelativeTime() time.Duration {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
+
+	return l.relative
+}
+
+// setRange sets the time components from start and end in local time.
+// The caller must hold the lock.
+func (l *LogEvent) setRange(start time.Time, end time.Time) {
+	start = start.Local()
+	end = end.Local()
+
+	l.startYear = start.Year()
+	l.startMonth = int(start.Month())
+	l.startDay = start.Day()
+	l.startHour = start.Hour()
+	l.startMinute = start.Minute()
+
+	l.endYear = end.Year()
+	l.endMonth = int(end.Month())
+	l.endDay = end.Day()
+	l.endHour = end.Hour()
+	l.endMinute = end.Minute()
+}
+
+// BeforeGet prepares the input parameters before fetching log events.
+// It validates the state and sets all necessary query parameters.
+// A relative time range is moved forward to end at the current time.
+// For the Next direction, the query of the first page is reused, so that all pages belong
+// to the same time range. The input is a template: GroupInputs returns the input of each log group.
+func (l *LogEvent) BeforeGet(input *awsr.LogEventInput, direct Direction) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if direct == Next {
+		if !l.hasNext {
+			return fmt.Errorf("no more log events to load")
+		}
+		input.LogGroupName = l.pageInput.LogGroupName
+		input.LogStreamNames = l.pageInput.LogStreamNames
+		input.FilterPattern = l.pageInput.FilterPattern
+		input.StartTime = l.pageInput.StartTime
+		input.EndTime = l.pageInput.EndTime
+		input.Limit = l.pageInput.Limit
+		return nil
+	}
+
+	if l.relative > 0 {
+		l.setRange(time.Now().Add(-l.relative), time.Now())
+	}
 	if l.isInValid() {
-		log.Fatalf("invalid log event state")
+		return fmt.Errorf("invalid log event state: select a log group and a time range")
+	}
+	if err := awsr.CheckLogStreamNames(l.logStreamNames); err != nil {
+		return err
 	}
 
-	input.LogGroupName = l.logGroupName
+	input.LogGroupName = l.logGroupNames[0]
 	input.LogStreamNames = l.logStreamNames
 	input.FilterPattern = l.filterPatern
+	input.OutputFile = l.outputFile
+	input.OutputFormat = l.outputFormat
+	input.Limit = l.eventsInPage
 	input.StartTime = time.Date(l.startYear, time.Month(l.startMonth), l.startDay, l.startHour, l.startMinute, 0, 0, time.Local)
 	input.EndTime = time.Date(l.endYear, time.Month(l.endMonth), l.endDay, l.endHour, l.endMinute, 0, 0, time.Local)
+	return nil
 }
 
-// isInValid checks if the LogEvent state has invalid or missing required fields.
-// Returns true if any time component is zero or log group name is empty.
-func (l *LogEvent) isInValid() bool {
+// GroupInputs returns an input per log group, copied from an input prepared by BeforeGet.
+// For the Next direction, only the log groups with more events are included,
+// each with its own next page token.
+func (l *LogEvent) GroupInputs(input *awsr.LogEventInput, direct Direction) []*awsr.LogEventInput {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	groups := l.logGroupNames
+	if direct == Next {
+		groups = l.pageGroups
+	}
+
+	var inputs []*awsr.LogEventInput
+	for _, group := range groups {
+		groupInput := *input
+		groupInput.LogGroupName = group
+		if direct == Next {
+			token, ok := l.nextTokens[group]
+			if !ok {
+				continue
+			}
+			groupInput.NextToken = token
+		}
+		inputs = append(inputs, &groupInput)
+	}
+	return inputs
+}
+
+// AfterGet updates the pagination state after fetching a page of log events per log group,
+// and merges the events of several log groups in time order.
+// The outputs are in the order of the inputs; a nil output is a log group that failed,
+// which keeps its next page token so that loading more retries it.
+// It returns false if the page is stale, i.e. another page was loaded since it was requested,
+// in which case the events must be discarded.
+func (l *LogEvent) AfterGet(inputs []*awsr.LogEventInput, outputs []*awsr.LogEventOutput, direct Direction) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch direct {
+	case Next:
+		if !l.hasNext {
+			return false
+		}
+		for _, input := range inputs {
+			if input.NextToken != l.nextTokens[input.LogGroupName] {
+				return false
+			}
+		}
+	case Home:
+		if len(inputs) == 0 {
+			return false
+		}
+		input := inputs[0]
+		l.pageInput = awsr.LogEventInput{
+			LogGroupName:   input.LogGroupName,
+			LogStreamNames: input.LogStreamNames,
+			FilterPattern:  input.FilterPattern,
+			StartTime:      input.StartTime,
+			EndTime:        input.EndTime,
+			Limit:          input.Limit,
+		}
+		l.pageGroups = nil
+		for _, input := range inputs {
+			l.pageGroups = append(l.pageGroups, input.LogGroupName)
+		}
+		l.loadedEvents = 0
+		l.droppedEvents = 0
+		l.events = nil
+		l.eventGroups = nil
+		l.visual = false
+		l.nextTokens = make(map[string]*string)
+	}
+
+	var events []cwlTypes.FilteredLogEvent
+	var groups []string
+	for i, output := range outputs {
+		if output == nil {
+			continue
+		}
+		group := inputs[i].LogGroupName
+		events = append(events, output.LogEvents...)
+		for range output.LogEvents {
+			groups = append(groups, group)
+		}
+
+		// FilterLogEvents may return a page with fewer events, even none, while more remain,
+		// so only the token tells whether there is a next page
+		if output.NextToken != nil && !l.following {
+			l.nextTokens[group] = output.NextToken
+		} else {
+			delete(l.nextTokens, group)
+		}
+	}
+	l.hasNext = len(l.nextTokens) > 0
+
+	l.loadedEvents += len(events)
+	l.events = append(l.events, events...)
+	l.eventGroups = append(l.eventGroups, groups...)
+	if len(l.pageGroups) > 1 {
+		// merging moves the loaded events, so a visual selection no longer holds the same ones
+		l.events, l.eventGroups = sortEvents(l.events, l.eventGroups)
+		l.visual = false
+	}
+	l.trimEvents()
+	if direct == Next {
+		l.markSeen(events, groups)
+	}
+	return true
+}
+
+// sortEvents sorts events and their log groups by timestamp,
+// keeping the order of events with the same timestamp.
+func sortEvents(events []cwlTypes.FilteredLogEvent, groups []string) ([]cwlTypes.FilteredLogEvent, []string) {
+	order := make([]int, len(events))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(aws.ToInt64(events[a].Timestamp), aws.ToInt64(events[b].Timestamp))
+	})
+
+	sortedEvents := make([]cwlTypes.FilteredLogEvent, len(events))
+	sortedGroups := make([]string, len(events))
+	for i, j := range order {
+		sortedEvents[i] = events[j]
+		sortedGroups[i] = groups[j]
+	}
+	return sortedEvents, sortedGroups
+}
+
+// IsMerged returns true if the loaded log events are merged from several log groups.
+func (l *LogEvent) IsMerged() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.pageGroups) > 1
+}
+
+// GetEventGroups returns the log group of each log event shown in the log viewer.
+func (l *LogEvent) GetEventGroups() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return slices.Clone(l.eventGroups)
+}
+
+// GetEventGroup returns the log group of the i-th log event shown in the log viewer.
+func (l *LogEvent) GetEventGroup(i int) string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if i < 0 || i >= len(l.eventGroups) {
+		return ""
+	}
+	return l.eventGroups[i]
+}
+
+// GetEvents returns the log events shown in the log viewer, in the order they were loaded.
+func (l *LogEvent) GetEvents() []cwlTypes.FilteredLogEvent {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return slices.Clone(l.events)
+}
+
+// GetEvent returns the i-th log event shown in the log viewer.
+func (l *LogEvent) GetEvent(i int) (cwlTypes.FilteredLogEvent, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if i < 0 || i >= len(l.events) {
+		return cwlTypes.FilteredLogEvent{}, false
+	}
+	return l.events[i], true
+}
+
+// SetSearch sets the search of the log viewer. An empty query clears it.
+// The query is matched literally ignoring case, or as a regular expression if regex is true.
+func (l *LogEvent) SetSearch(query string, regex bool) error {
+	var re *regexp.Regexp
+	if query != "" {
+		var err error
+		re, err = view.CompileSearch(query, regex)
+		if err != nil {
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.searchQuery = query
+	l.searchRegex = regex
+	l.search = re
+	l.searchMatch = 0
+	return nil
+}
+
+// GetSearch returns the compiled search of the log viewer, or nil if there is none.
+func (l *LogEvent) GetSearch() *regexp.Regexp {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.search
+}
+
+// GetSearchMatch returns the index of the current search match.
+func (l *LogEvent) GetSearchMatch() int {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
-	if l.logGroupName == "" {
+	return l.searchMatch
+}
+
+// SetSearchMatch sets the index of the current search match.
+func (l *LogEvent) SetSearchMatch(i int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.searchMatch = i
+}
+
+// IsTableView returns true if log events are shown as a table instead of text.
+func (l *LogEvent) IsTableView() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.tableView
+}
+
+// ToggleTableView switches between the text and the table view of log events
+// and returns true if the table view is now shown.
+func (l *LogEvent) ToggleTableView() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tableView = !l.tableView
+	return l.tableView
+}
+
+// StartVisual starts a visual selection of log events at the i-th event.
+func (l *LogEvent) StartVisual(i int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.visual = true
+	l.visualAnchor = i
+}
+
+// StopVisual ends the visual selection of log events.
+func (l *LogEvent) StopVisual() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.visual = false
+}
+
+// GetVisual returns the index of the event the visual selection started at,
+// and false if there is no visual selection.
+func (l *LogEvent) GetVisual() (int, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.visualAnchor, l.visual
+}
+
+// IsUTC returns true if event times in the table view are shown in UTC instead of local time.
+func (l *LogEvent) IsUTC() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.utc
+}
+
+// ToggleUTC switches event times in the table view between local time and UTC.
+func (l *LogEvent) ToggleUTC() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.utc = !l.utc
+}
+
+// GetMessageMode returns how log messages are rendered in the log viewer.
+func (l *LogEvent) GetMessageMode() view.MessageMode {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.messageMode
+}
+
+// NextMessageMode switches to the next message rendering mode and returns it.
+func (l *LogEvent) NextMessageMode() view.MessageMode {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.messageMode = l.messageMode.Next()
+	return l.messageMode
+}
+
+// HasNext returns true if there is a next page of log events available.
+func (l *LogEvent) HasNext() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.hasNext
+}
+
+// trimEvents drops the oldest log events beyond the most kept in the log viewer.
+// It drops a tenth more than needed, so that a full log viewer is not rendered again on every follow poll.
+// The caller must hold the lock.
+func (l *LogEvent) trimEvents() {
+	if l.maxEventsInView <= 0 || len(l.events) <= l.maxEventsInView {
+		return
+	}
+	n := len(l.events) - l.maxEventsInView*9/10
+	l.events = slices.Clone(l.events[n:])
+	l.eventGroups = slices.Clone(l.eventGroups[n:])
+	l.droppedEvents += n
+
+	l.visualAnchor -= n
+	if l.visualAnchor < 0 {
+		l.visual = false
+	}
+}
+
+// GetDroppedEvents returns the number of the oldest log events dropped from the log viewer
+// since the first page, to keep at most the configured number of events.
+func (l *LogEvent) GetDroppedEvents() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.droppedEvents
+}
+
+// GetLoadedEvents returns the number of log events loaded into the viewer by paging.
+func (l *LogEvent) GetLoadedEvents() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.loadedEvents
+}
+
+// BeforeExport prepares the input parameters before saving log events to a file.
+// If the last export stopped early and the query and output are unchanged,
+// the input resumes it, appending to the same file.
+func (l *LogEvent) BeforeExport(input *awsr.LogEventInput) error {
+	if err := l.BeforeGet(input, Home); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.logGroupNames) > 1 {
+		return fmt.Errorf("log events can only be saved from a single log group")
+	}
+	l.exportProgress = awsr.ExportProgress{}
+	if !l.canResume(input) {
+		l.exportResume = nil
+		return nil
+	}
+
+	input.StartTime = l.exportResume.StartTime
+	input.EndTime = l.exportResume.EndTime
+	progress := *l.exportResume.Resume
+	input.Resume = &progress
+	l.exportProgress = progress
+	return nil
+}
+
+// canResume returns true if the input is the same export as the one that stopped early.
+// A relative time range only has to be of the same duration, as it moves with the current time.
+// The caller must hold the lock.
+func (l *LogEvent) canResume(input *awsr.LogEventInput) bool {
+	r := l.exportResume
+	if r == nil {
+		return false
+	}
+	if r.LogGroupName != input.LogGroupName ||
+		!slices.Equal(r.LogStreamNames, input.LogStreamNames) ||
+		r.FilterPattern != input.FilterPattern ||
+		r.OutputFile != input.OutputFile ||
+		r.OutputFormat != input.OutputFormat {
+		return false
+	}
+	if l.relative > 0 {
+		return l.relative == l.exportRelative
+	}
+	return l.exportRelative == 0 && r.StartTime.Equal(input.StartTime) && r.EndTime.Equal(input.EndTime)
+}
+
+// SetExportProgress records the progress of the running export.
+func (l *LogEvent) SetExportProgress(progress awsr.ExportProgress) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.exportProgress = progress
+}
+
+// GetExportProgress returns the progress of the running or last export.
+func (l *LogEvent) GetExportProgress() awsr.ExportProgress {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.exportProgress
+}
+
+// AfterExport records where an export stopped, so that the next one can resume it.
+// Nothing is recorded if the export completed or did not write any page.
+func (l *LogEvent) AfterExport(input *awsr.LogEventInput) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.exportProgress.NextToken == nil || l.exportProgress.Pages == 0 {
+		l.exportResume = nil
+		return
+	}
+
+	progress := l.exportProgress
+	resume := *input
+	resume.Ctx = nil
+	resume.OnProgress = nil
+	resume.Resume = &progress
+	l.exportResume = &resume
+	l.exportRelative = l.relative
+}
+
+// CanResumeExport returns true if the last export stopped early and can be resumed.
+func (l *LogEvent) CanResumeExport() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.exportResume != nil
+}
+
+// IsFollowing returns true if new log events are being tailed.
+func (l *LogEvent) IsFollowing() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.following
+}
+
+// SetFollowing enables or disables tailing of new log events.
+// Following continues from the newest loaded event and so also reads
+// the remaining pages, hence the next page token is dropped.
+func (l *LogEvent) SetFollowing(following bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.following = following
+	if following {
+		l.hasNext = false
+	}
+}
+
+// ResetFollow records the events loaded by a regular fetch as already seen,
+// so that following continues from the newest of them.
+func (l *LogEvent) ResetFollow(input *awsr.LogEventInput) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.followGeneration++
+	l.follower = awsr.NewFollower(input.StartTime)
+	l.markSeen(l.events, l.eventGroups)
+}
+
+// BeforeFollow prepares the input parameters for the next follow poll.
+// It returns a generation number that must be passed to AfterFollow.
+func (l *LogEvent) BeforeFollow(input *awsr.LogEventInput) (int, error) {
+	if err := l.BeforeGet(input, Home); err != nil {
+		return 0, err
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.follower == nil {
+		return 0, fmt.Errorf("load log events before following them")
+	}
+	input.StartTime = l.follower.From()
+	input.EndTime = time.Now()
+	return l.followGeneration, nil
+}
+
+// AfterFollow returns the events of a follow poll that were not seen before,
+// with their log groups, in time order. The outputs are in the order of the inputs,
+// with a nil output for a log group that failed.
+// Results of a poll started before the last ResetFollow are discarded.
+func (l *LogEvent) AfterFollow(generation int, inputs []*awsr.LogEventInput, outputs []*awsr.LogEventOutput) ([]cwlTypes.FilteredLogEvent, []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if generation != l.followGeneration {
+		return nil, nil
+	}
+
+	var events []cwlTypes.FilteredLogEvent
+	var groups []string
+	for i, output := range outputs {
+		if output == nil {
+			continue
+		}
+		group := inputs[i].LogGroupName
+		for _, event := range l.follower.Unseen(group, output.LogEvents) {
+			events = append(events, event)
+			groups = append(groups, group)
+		}
+	}
+	if len(outputs) > 1 {
+		events, groups = sortEvents(events, groups)
+	}
+	l.events = append(l.events, events...)
+	l.eventGroups = append(l.eventGroups, groups...)
+	l.trimEvents()
+	return events, groups
+}
+
+// markSeen remembers the events as seen by the follow polls, which then start after them.
+// The caller must hold the lock.
+func (l *LogEvent) markSeen(events []cwlTypes.FilteredLogEvent, groups []string) {
+	if l.follower == nil {
+		return
+	}
+	byGroup := make(map[string][]cwlTypes.FilteredLogEvent)
+	for i, event := range events {
+		byGroup[groups[i]] = append(byGroup[groups[i]], event)
+	}
+	for group, events := range byGroup {
+		l.follower.Unseen(group, events)
+	}
+}
+
+// isInValid checks if the LogEvent state has invalid or missing required fields.
+// Returns true if any date component is zero or log group name is empty.
+// Hour and minute are not checked since zero is a valid value for them.
+// The caller must hold the lock.
+func (l *LogEvent) isInValid() bool {
+	if len(l.logGroupNames) == 0 || l.logGroupNames[0] == "" {
 		return true
 	}
 	return l.startYear == 0 ||
 		l.startMonth == 0 ||
 		l.startDay == 0 ||
-		l.startHour == 0 ||
-		l.startMinute == 0 ||
 		l.endYear == 0 ||
 		l.endMonth == 0 ||
-		l.endDay == 0 ||
-		l.endHour == 0 ||
-		l.endMinute == 0
+		l.endDay == 0
 }
 
 // SetTime updates a specific time component based on the widget label.
 // It converts the text value to integer and updates the corresponding field.
-func (l *LogEvent) SetTime(label string, text string) {
+// Changing a component turns a relative time range into a custom absolute one.
+func (l *LogEvent) SetTime(label string, text string) error {
+	value, err := string2int(text)
+	if err != nil {
+		return err
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	var field *int
 	switch label {
 	case view.WidgetNames[view.StartYearDropDown]:
-		l.startYear = string2int(text)
+		field = &l.startYear
 	case view.WidgetNames[view.StartMonthDropDown]:
-		l.startMonth = string2int(text)
+		field = &l.startMonth
 	case view.WidgetNames[view.StartDayDropDown]:
-		l.startDay = string2int(text)
+		field = &l.startDay
 	case view.WidgetNames[view.StartHourDropDown]:
-		l.startHour = string2int(text)
+		field = &l.startHour
 	case view.WidgetNames[view.StartMinuteDropDown]:
-		l.startMinute = string2int(text)
+		field = &l.startMinute
 	case view.WidgetNames[view.EndYearDropDown]:
-		l.endYear = string2int(text)
+		field = &l.endYear
 	case view.WidgetNames[view.EndMonthDropDown]:
-		l.endMonth = string2int(text)
+		field = &l.endMonth
 	case view.WidgetNames[view.EndDayDropDown]:
-		l.endDay = string2int(text)
+		field = &l.endDay
 	case view.WidgetNames[view.EndHourDropDown]:
-		l.endHour = string2int(text)
+		field = &l.endHour
 	case view.WidgetNames[view.EndMinuteDropDown]:
-		l.endMinute = string2int(text)
+		field = &l.endMinute
+	default:
+		return nil
+	}
+
+	if *field != value {
+		*field = value
+		l.relative = 0
 	}
+	return nil
 }
 
 // string2int converts a string to integer.
-// It terminates the program if conversion fails.
-func string2int(s string) int {
+// It returns an error if the string is not a number.
+func string2int(s string) (int, error) {
 	i, err := strconv.Atoi(s)
 	if err != nil {
-		log.Fatalf("unable to list tables, %v", err)
+		return 0, fmt.Errorf("unable to convert %q to integer: %w", s, err)
 	}
-	return i
+	return i, nil
 }
 
 // Print displays the current log event settings in the provided text view.
 // It shows log group, streams, filter pattern, time range, and other query parameters.
-func (l *LogEvent) Print(textView *tview.TextView) {
-	fmt.Fprintf(textView, "------------------------------------- \n")
-	fmt.Fprintf(textView, "[YOUR SETTING]\n")
-	fmt.Fprintf(textView, "LogGroup: %s\n", l.logGroupName)
+// The text is escaped, as the log viewer renders color tags.
+func (l *LogEvent) Print(textView *tview.TextView, keys *view.Keymap) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "------------------------------------- \n")
+	fmt.Fprintf(&b, "[YOUR SETTING]\n")
+	fmt.Fprintf(&b, "LogGroup: %s\n", strings.Join(l.logGroupNames, ", "))
 	if len(l.logStreamNames) == 0 {
-		fmt.Fprintf(textView, "LogStreams: %s\n", "ALL")
+		fmt.Fprintf(&b, "LogStreams: %s\n", "ALL")
 	} else {
-		fmt.Fprintf(textView, "LogStreams: %s\n", l.logStreamNames)
+		fmt.Fprintf(&b, "LogStreams: %s\n", l.logStreamNames)
 	}
-	fmt.Fprintf(textView, "LogStreams: %s\n", l.logStreamNames)
-	fmt.Fprintf(textView, "FilterPaterm: %s\n", l.filterPatern)
+	fmt.Fprintf(&b, "LogStreams: %s\n", l.logStreamNames)
+	fmt.Fprintf(&b, "FilterPaterm: %s\n", l.filterPatern)
 
-	fmt.Fprintf(textView, "%s/%s/%s %s:%s\n",
+	fmt.Fprintf(&b, "%s/%s/%s %s:%s\n",
 		strconv.Itoa(l.startYear),
 		strconv.Itoa(l.startMonth),
 		strconv.Itoa(l.startDay),
 		strconv.Itoa(l.startHour),
 		strconv.Itoa(l.startMinute),
 	)
-	fmt.Fprintf(textView, "    ~     \n")
-	fmt.Fprintf(textView, "%s/%s/%s %s:%s\n",
+	fmt.Fprintf(&b, "    ~     \n")
+	fmt.Fprintf(&b, "%s/%s/%s %s:%s\n",
 		strconv.Itoa(l.endYear),
 		strconv.Itoa(l.endMonth),
 		strconv.Itoa(l.endDay),
@@ -251,7 +1013,9 @@ func (l *LogEvent) Print(textView *tview.TextView) {
 		strconv.Itoa(l.endMinute),
 	)
 
-	fmt.Fprintf(textView, "MaxEvent: %s\n", "1000")
-	fmt.Fprintf(textView, "You can get all log events by pressing 'Save Button'.\n")
-	fmt.Fprintf(textView, "------------------------------------- \n")
+	fmt.Fprintf(&b, "PageSize: %d\n", l.eventsInPage)
+	fmt.Fprintf(&b, "Press '%s' in this view to load the next page, or 'Save Button' to save all log events.\n", keys.Hint(view.ActionMore))
+	fmt.Fprintf(&b, "Message: %s (press '%s' to change)\n", view.MessageModeNames[l.messageMode], keys.Hint(view.ActionMessageMode))
+	fmt.Fprintf(&b, "------------------------------------- \n")
+	fmt.Fprint(textView, tview.Escape(b.String()))
 }
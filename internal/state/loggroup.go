@@ -2,6 +2,7 @@
 package state
 
 import (
+	"slices"
 	"sync"
 	// "log"
 
@@ -16,6 +17,8 @@ type LogGroup struct {
 	hasNext      bool
 	hasPrev      bool
 	pageTokens   map[int]*string
+	pageSize     int32
+	selected     []string
 	mu           sync.RWMutex
 }
 
@@ -26,6 +29,7 @@ func (l *LogGroup) BeforeGet(input *awsr.LogGroupInput, direct Direction) {
 	defer l.mu.RUnlock()
 
 	input.FilterPattern = l.filterPatern
+	input.Limit = l.pageSize
 
 	switch direct {
 	case Next:
@@ -54,7 +58,7 @@ func (l *LogGroup) AfterGet(output *awsr.LogGroupOutput, direct Direction) {
 		l.currentPage = 1
 	}
 
-	if output.NextToken != nil && len(output.LogGroups) == int(awsr.MaxItemsInLayout) {
+	if output.NextToken != nil && len(output.LogGroups) == int(l.pageSize) {
 		l.pageTokens[l.currentPage+1] = output.NextToken
 		l.hasNext = true
 	} else {
@@ -92,3 +96,37 @@ func (l *LogGroup) SetFilterPattern(filterPatern string) {
 
 	l.filterPatern = filterPatern
 }
+
+// ToggleSelected marks or unmarks a log group for viewing several log groups at once
+// and returns true if it is now marked.
+func (l *LogGroup) ToggleSelected(logGroupName string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if i := slices.Index(l.selected, logGroupName); i >= 0 {
+		l.selected = slices.Delete(l.selected, i, i+1)
+		return false
+	}
+	l.selected = append(l.selected, logGroupName)
+	return true
+}
+
+// GetSelected returns the marked log groups in the order they were marked.
+func (l *LogGroup) GetSelected() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return slices.Clone(l.selected)
+}
+
+// Reset clears the pagination state and the marked log groups, e.g. after switching AWS accounts.
+func (l *LogGroup) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.selected = nil
+	l.currentPage = 0
+	l.hasNext = false
+	l.hasPrev = false
+	l.pageTokens = make(map[int]*string)
+}
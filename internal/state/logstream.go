@@ -2,6 +2,7 @@
 package state
 
 import (
+	"slices"
 	"sync"
 
 	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
@@ -16,6 +17,8 @@ type LogStream struct {
 	hasNext      bool
 	hasPrev      bool
 	pageTokens   map[int]*string
+	pageSize     int32
+	selected     []string
 	mu           sync.RWMutex
 }
 
@@ -26,6 +29,7 @@ func (l *LogStream) BeforeGet(input *awsr.LogStreamInput, direct Direction) {
 	defer l.mu.RUnlock()
 
 	input.LogGroupName = l.logGroupName
+	input.Limit = l.pageSize
 
 	switch direct {
 	case Next:
@@ -53,7 +57,7 @@ func (l *LogStream) AfterGet(output *awsr.LogStreamOutput, direct Direction) {
 		l.currentPage = 1
 	}
 
-	if output.NextToken != nil && len(output.LogStreams) == int(awsr.MaxItemsInLayout) {
+	if output.NextToken != nil && len(output.LogStreams) == int(l.pageSize) {
 		l.pageTokens[l.currentPage+1] = output.NextToken
 		l.hasNext = true
 	} else {
@@ -85,9 +89,83 @@ func (l *LogStream) HasNext() bool {
 
 // SetLogGroupSelected updates the currently selected log group name
 // for which log streams will be fetched.
+// The marked log streams are cleared when the log group changes.
 func (l *LogStream) SetLogGroupSelected(logGroupName string) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	if l.logGroupName != logGroupName {
+		l.selected = nil
+	}
 	l.logGroupName = logGroupName
 }
+
+// ToggleSelected marks or unmarks a log stream and returns true if it is now marked.
+func (l *LogStream) ToggleSelected(logStreamName string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if i := slices.Index(l.selected, logStreamName); i >= 0 {
+		l.selected = slices.Delete(l.selected, i, i+1)
+		return false
+	}
+	l.selected = append(l.selected, logStreamName)
+	return true
+}
+
+// SelectAll marks the given log streams, e.g. those of the current page.
+func (l *LogStream) SelectAll(logStreamNames []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, name := range logStreamNames {
+		if !slices.Contains(l.selected, name) {
+			l.selected = append(l.selected, name)
+		}
+	}
+}
+
+// InvertSelected marks the given log streams that are unmarked and unmarks the others.
+// Log streams marked on other pages stay marked.
+func (l *LogStream) InvertSelected(logStreamNames []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, name := range logStreamNames {
+		if i := slices.Index(l.selected, name); i >= 0 {
+			l.selected = slices.Delete(l.selected, i, i+1)
+		} else {
+			l.selected = append(l.selected, name)
+		}
+	}
+}
+
+// ClearSelected unmarks all log streams.
+func (l *LogStream) ClearSelected() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.selected = nil
+}
+
+// GetSelected returns the marked log streams in the order they were marked.
+func (l *LogStream) GetSelected() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return slices.Clone(l.selected)
+}
+
+// Reset clears the pagination state, the selected log group and the marked log streams,
+// e.g. after switching AWS accounts.
+func (l *LogStream) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.logGroupName = ""
+	l.selected = nil
+	l.currentPage = 0
+	l.hasNext = false
+	l.hasPrev = false
+	l.pageTokens = make(map[int]*string)
+}
@@ -0,0 +1,38 @@
+// Package state manages the application state for the CloudWatch Log TUI.
+package state
+
+import (
+	"sync"
+)
+
+// Profile holds the AWS profile and region chosen in the profile picker
+// before they are applied.
+type Profile struct {
+	profile string
+	region  string
+	mu      sync.RWMutex
+}
+
+// SetProfile sets the chosen profile name.
+func (p *Profile) SetProfile(profile string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.profile = profile
+}
+
+// SetRegion sets the chosen region. An empty region means the profile's default.
+func (p *Profile) SetRegion(region string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.region = region
+}
+
+// Get returns the chosen profile and region.
+func (p *Profile) Get() (string, string) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.profile, p.region
+}
@@ -0,0 +1,111 @@
+// Package state manages the application state for the CloudWatch Log TUI.
+package state
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
+)
+
+// Query manages the state for CloudWatch Logs Insights queries,
+// including the target log groups, query string, time range and running query.
+type Query struct {
+	logGroupNames []string
+	queryString   string
+	timeRange     time.Duration
+	queryId       string
+	mu            sync.RWMutex
+}
+
+// ToggleLogGroup adds the log group to the query targets, or removes it if already present.
+func (q *Query) ToggleLogGroup(logGroupName string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if i := slices.Index(q.logGroupNames, logGroupName); i >= 0 {
+		q.logGroupNames = slices.Delete(q.logGroupNames, i, i+1)
+		return
+	}
+	q.logGroupNames = append(q.logGroupNames, logGroupName)
+}
+
+// ClearLogGroups removes all log groups from the query targets.
+func (q *Query) ClearLogGroups() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.logGroupNames = []string{}
+}
+
+// GetLogGroupsSelected returns the log groups the query runs against.
+func (q *Query) GetLogGroupsSelected() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return slices.Clone(q.logGroupNames)
+}
+
+// SetQueryString updates the Logs Insights query string.
+func (q *Query) SetQueryString(queryString string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.queryString = queryString
+}
+
+// SetTimeRange sets how far back from now the query searches.
+// The range is given as a duration such as "15m", "3h" or "7d".
+func (q *Query) SetTimeRange(text string) error {
+	d, err := config.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.timeRange = d
+	return nil
+}
+
+// SetQueryId records the ID of the running query.
+func (q *Query) SetQueryId(queryId string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.queryId = queryId
+}
+
+// GetQueryId returns the ID of the running query, or an empty string if none.
+func (q *Query) GetQueryId() string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return q.queryId
+}
+
+// BeforeStart prepares the input parameters before starting a query.
+// It returns an error if no log group is selected or the query is empty.
+func (q *Query) BeforeStart(input *awsr.QueryInput) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if len(q.logGroupNames) == 0 {
+		return fmt.Errorf("no log group selected")
+	}
+	if strings.TrimSpace(q.queryString) == "" {
+		return fmt.Errorf("query is empty")
+	}
+
+	now := time.Now()
+	input.LogGroupNames = slices.Clone(q.logGroupNames)
+	input.QueryString = q.queryString
+	input.StartTime = now.Add(-q.timeRange)
+	input.EndTime = now
+	return nil
+}
@@ -2,6 +2,13 @@
 // It maintains the current state of log groups, streams, and events during navigation.
 package state
 
+import (
+	"time"
+
+	awsr "github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
+)
+
 // Direction specifies navigation Direction in logs
 type Direction int
 
@@ -20,21 +27,58 @@ type UIState struct {
 	LogGroup  *LogGroup
 	LogStream *LogStream
 	LogEvent  *LogEvent
+	Query     *Query
+	Profile   *Profile
+	Bookmark  *Bookmark
 }
 
 // New creates a new UIState instance with initialized sub-components.
-// It returns a UIState with empty log collections ready for population.
-func New() *UIState {
+// It returns a UIState with empty log collections ready for population,
+// using the page sizes, default time range and export defaults of the configuration.
+func New(cfg *config.Config) *UIState {
+	relative, err := cfg.RelativeTime()
+	if err != nil {
+		relative = time.Hour
+	}
+	outputFormat, err := awsr.ParseOutputFormat(cfg.Export.Format)
+	if err != nil {
+		outputFormat = awsr.FormatAuto
+	}
+
 	return &UIState{
 		LogEvent: &LogEvent{
 			enableOutputFile: false,
 			logStreamNames:   make([]string, 0),
+			nextTokens:       make(map[string]*string),
+			eventsInPage:     cfg.MaxEvents,
+			maxEventsInView:  int(cfg.MaxEventsPerView),
+			defaultRelative:  relative,
+			outputFile:       cfg.Export.File,
+			outputFormat:     outputFormat,
 		},
 		LogGroup: &LogGroup{
 			pageTokens: make(map[int]*string),
+			pageSize:   cfg.PageSize,
 		},
 		LogStream: &LogStream{
 			pageTokens: make(map[int]*string),
+			pageSize:   cfg.PageSize,
+		},
+		Query: &Query{
+			logGroupNames: make([]string, 0),
+			timeRange:     time.Hour,
 		},
+		Profile:  &Profile{},
+		Bookmark: &Bookmark{},
 	}
 }
+
+// Reset clears pagination tokens and selections that belong to the current
+// AWS account and region, so that data can be reloaded from another one.
+func (s *UIState) Reset() {
+	s.LogGroup.Reset()
+	s.LogStream.Reset()
+	s.LogEvent.SetLogGroupSelected("")
+	s.LogEvent.SetLogStreamsSelected([]string{})
+	s.Query.ClearLogGroups()
+}
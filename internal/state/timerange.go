@@ -0,0 +1,158 @@
+// Package state manages the application state for the CloudWatch Log TUI.
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
+)
+
+// TimeRange is a time window parsed from a user expression.
+// Relative is non-zero when the window is "the last Relative" up to now,
+// so that it can be recomputed each time events are fetched.
+type TimeRange struct {
+	Start    time.Time
+	End      time.Time
+	Relative time.Duration
+}
+
+// dateTimeLayouts are the absolute formats accepted in time range expressions.
+var dateTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// dateLayout is the date-only format accepted in time range expressions.
+const dateLayout = "2006-01-02"
+
+// clockLayout is the time-of-day format accepted in time range expressions.
+const clockLayout = "15:04"
+
+// ParseTimeRange parses a time range expression relative to now, in local time.
+// Accepted forms are:
+//
+//	-30m, 2h, 7d                      the last 30 minutes, 2 hours, 7 days
+//	2024-06-01T10:00                  from the given time until now
+//	2024-06-01, yesterday, today      the whole day
+//	yesterday 09:00                   from the given time until now
+//	A..B                              from A to B, e.g. yesterday 09:00..10:00
+//
+// In a range, B may be a bare time of day, which is taken on A's date.
+func ParseTimeRange(expr string, now time.Time) (TimeRange, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return TimeRange{}, fmt.Errorf("time range is empty")
+	}
+
+	left, right, isRange := strings.Cut(expr, "..")
+	if !isRange {
+		if d, err := config.ParseRelative(left); err == nil {
+			return TimeRange{Start: now.Add(-d), End: now, Relative: d}, nil
+		}
+		start, wholeDay, err := parseTimePoint(left, now, now)
+		if err != nil {
+			return TimeRange{}, err
+		}
+		end := now
+		if wholeDay {
+			end = start.AddDate(0, 0, 1)
+		}
+		return TimeRange{Start: start, End: end}, nil
+	}
+
+	start, _, err := parseTimePoint(left, now, now)
+	if err != nil {
+		return TimeRange{}, err
+	}
+	end, wholeDay, err := parseTimePoint(right, now, start)
+	if err != nil {
+		return TimeRange{}, err
+	}
+	if wholeDay {
+		end = end.AddDate(0, 0, 1)
+	}
+	if !start.Before(end) {
+		return TimeRange{}, fmt.Errorf("time range %q ends before it starts", expr)
+	}
+	return TimeRange{Start: start, End: end}, nil
+}
+
+// ParsePreset parses a time preset label such as "last 15m" into its duration.
+func ParsePreset(label string) (time.Duration, error) {
+	return config.ParseDuration(strings.TrimPrefix(label, "last "))
+}
+
+// FormatRelative formats the duration of a relative time range in the largest whole unit,
+// e.g. "15m", "3h" or "7d", so that ParseTimeRange reads it back.
+func FormatRelative(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return d.String()
+	}
+}
+
+// parseTimePoint parses one side of a time range expression.
+// A bare time of day is taken on base's date. wholeDay is true when only a date was given.
+func parseTimePoint(text string, now time.Time, base time.Time) (t time.Time, wholeDay bool, err error) {
+	text = strings.TrimSpace(text)
+
+	if text == "now" {
+		return now, false, nil
+	}
+	if d, err := config.ParseRelative(text); err == nil {
+		return now.Add(-d), false, nil
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return t, false, nil
+		}
+	}
+	if t, err := time.ParseInLocation(dateLayout, text, time.Local); err == nil {
+		return t, true, nil
+	}
+
+	day, clock, hasClock := strings.Cut(text, " ")
+	var date time.Time
+	switch day {
+	case "today":
+		date = startOfDay(now)
+	case "yesterday":
+		date = startOfDay(now).AddDate(0, 0, -1)
+	default:
+		c, err := time.ParseInLocation(clockLayout, text, time.Local)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid time %q", text)
+		}
+		return atClock(startOfDay(base), c), false, nil
+	}
+
+	if !hasClock {
+		return date, true, nil
+	}
+	c, err := time.ParseInLocation(clockLayout, strings.TrimSpace(clock), time.Local)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %q", text)
+	}
+	return atClock(date, c), false, nil
+}
+
+// startOfDay returns midnight of t's day in local time.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// atClock returns day at the hour and minute of clock.
+func atClock(day time.Time, clock time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+}
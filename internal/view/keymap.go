@@ -0,0 +1,458 @@
+// Package view provides UI components and layouts for the CloudWatch Log TUI.
+package view
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Action is a command that can be bound to keys.
+type Action int
+
+const (
+	// ActionNone is returned for keys that are not bound to an action
+	ActionNone Action = iota
+	ActionUp
+	ActionDown
+	ActionFocusNext
+	ActionSelect
+	ActionBack
+	ActionHelp
+	ActionSearch
+	ActionNextMatch
+	ActionPrevMatch
+	ActionReload
+	ActionSave
+	ActionToggleFollow
+	ActionMore
+	ActionMessageMode
+	ActionToggleView
+	ActionToggleUTC
+	ActionBookmark
+	ActionBookmarks
+	ActionInsights
+	ActionProfile
+	ActionRename
+	ActionDelete
+	ActionTestFilter
+	ActionToggleRegex
+	ActionToggleSelect
+	ActionSelectAll
+	ActionClearSelection
+	ActionInvertSelection
+	ActionConsoleLink
+	ActionVisual
+	ActionYank
+	ActionYankJSON
+	numActions
+)
+
+// Scope is a group of widgets in which a key means the same action.
+// A key can be bound to different actions in different scopes.
+type Scope int
+
+const (
+	// ScopeLogGroup is the log group table
+	ScopeLogGroup Scope = iota
+	// ScopeLogStream is the log stream table
+	ScopeLogStream
+	// ScopeForm is the time range dropdowns and buttons of the log event page
+	ScopeForm
+	// ScopeLogView is the log event text view and the event table
+	ScopeLogView
+	// ScopeEventDetail is the detail pane of the selected event in the event table
+	ScopeEventDetail
+	// ScopeQuery is the Logs Insights query page
+	ScopeQuery
+	// ScopeProfile is the profile and region picker
+	ScopeProfile
+	// ScopeBookmark is the bookmarks page
+	ScopeBookmark
+	// ScopeInput is every other text input. Keys typing a character are never bound in it,
+	// nor in the filter pattern and search inputs
+	ScopeInput
+	// ScopeFilterInput is the filter pattern input of the log event page
+	ScopeFilterInput
+	// ScopeSearchInput is the search input of the log viewer
+	ScopeSearchInput
+	// ScopeHelp is the help overlay
+	ScopeHelp
+	numScopes
+)
+
+// ScopeNames maps scopes to the names shown in messages.
+var ScopeNames = map[Scope]string{
+	ScopeLogGroup:    "log groups",
+	ScopeLogStream:   "log streams",
+	ScopeForm:        "log event form",
+	ScopeLogView:     "log viewer",
+	ScopeEventDetail: "event detail",
+	ScopeQuery:       "Insights query",
+	ScopeProfile:     "profile picker",
+	ScopeBookmark:    "bookmarks",
+	ScopeInput:       "text inputs",
+	ScopeFilterInput: "filter pattern input",
+	ScopeSearchInput: "search input",
+	ScopeHelp:        "help",
+}
+
+// typed reports whether the scope is a text input, in which keys typing a character are not bound.
+func (s Scope) typed() bool {
+	return s == ScopeInput || s == ScopeFilterInput || s == ScopeSearchInput
+}
+
+// PageScopes lists the scopes of the widgets on each page.
+var PageScopes = map[Page][]Scope{
+	LogGroupAndStreamPage: {ScopeLogGroup, ScopeLogStream, ScopeInput},
+	LogEventPage:          {ScopeForm, ScopeLogView, ScopeEventDetail, ScopeFilterInput, ScopeSearchInput, ScopeInput},
+	InsightsPage:          {ScopeQuery, ScopeInput},
+	ProfilePage:           {ScopeProfile},
+	BookmarkPage:          {ScopeBookmark, ScopeInput},
+}
+
+// ActionInfo describes an action: the name used in the keys section of the
+// config file, what it does, its default keys and the scopes it is available in.
+type ActionInfo struct {
+	Name        string
+	Description string
+	Keys        []string
+	Scopes      []Scope
+}
+
+// Actions lists the actions in the order they are documented.
+var Actions = [numActions]ActionInfo{
+	ActionUp: {"up", "move up", []string{"k", "Up"},
+		[]Scope{ScopeLogGroup, ScopeLogStream, ScopeForm, ScopeLogView, ScopeEventDetail, ScopeQuery, ScopeProfile, ScopeBookmark, ScopeHelp}},
+	ActionDown: {"down", "move down, loading more events at the end of the log viewer", []string{"j", "Down"},
+		[]Scope{ScopeLogGroup, ScopeLogStream, ScopeForm, ScopeLogView, ScopeEventDetail, ScopeQuery, ScopeProfile, ScopeBookmark, ScopeHelp}},
+	ActionFocusNext: {"focus-next", "move the focus to the next widget", []string{"Tab"},
+		[]Scope{ScopeLogGroup, ScopeLogStream, ScopeForm, ScopeLogView, ScopeEventDetail, ScopeQuery, ScopeProfile,
+			ScopeInput, ScopeFilterInput, ScopeSearchInput}},
+	ActionSelect: {"select", "select the item, press the button or submit the input", []string{"Enter"},
+		[]Scope{ScopeLogGroup, ScopeLogStream, ScopeForm, ScopeQuery, ScopeProfile, ScopeBookmark,
+			ScopeInput, ScopeFilterInput, ScopeSearchInput}},
+	ActionBack: {"back", "go back or cancel", []string{"Esc"},
+		[]Scope{ScopeForm, ScopeLogView, ScopeEventDetail, ScopeQuery, ScopeProfile, ScopeBookmark,
+			ScopeInput, ScopeFilterInput, ScopeSearchInput, ScopeHelp}},
+	ActionHelp: {"help", "show the keys of the focused panel", []string{"?", "F1"},
+		[]Scope{ScopeLogGroup, ScopeLogStream, ScopeForm, ScopeLogView, ScopeEventDetail, ScopeQuery, ScopeProfile, ScopeBookmark,
+			ScopeInput, ScopeFilterInput, ScopeSearchInput, ScopeHelp}},
+	ActionSearch: {"search", "search log groups or the loaded log events", []string{"/"},
+		[]Scope{ScopeLogGroup, ScopeLogView}},
+	ActionNextMatch: {"next-match", "jump to the next search match", []string{"n"},
+		[]Scope{ScopeLogView}},
+	ActionPrevMatch: {"prev-match", "jump to the previous search match", []string{"N"},
+		[]Scope{ScopeLogView}},
+	ActionReload: {"reload", "reload the log groups, streams or events", []string{"Ctrl-R"},
+		[]Scope{ScopeLogGroup, ScopeLogStream, ScopeLogView}},
+	ActionSave: {"save", "save the log events to the output file", []string{"Ctrl-S"},
+		[]Scope{ScopeForm, ScopeLogView}},
+	ActionToggleFollow: {"toggle-follow", "pause or resume following new log events", []string{"f"},
+		[]Scope{ScopeLogView}},
+	ActionMore: {"more", "load the next page of log events", []string{"m"},
+		[]Scope{ScopeLogView}},
+	ActionMessageMode: {"message-mode", "switch between raw, compact, pretty and colored JSON messages", []string{"J"},
+		[]Scope{ScopeLogView}},
+	ActionToggleView: {"toggle-view", "switch between the text view and the event table", []string{"t"},
+		[]Scope{ScopeLogView}},
+	ActionToggleUTC: {"toggle-utc", "switch the event table between local time and UTC", []string{"u"},
+		[]Scope{ScopeLogView}},
+	ActionBookmark: {"bookmark", "bookmark the current log event view", []string{"b"},
+		[]Scope{ScopeLogView}},
+	ActionBookmarks: {"bookmarks", "open the bookmarks page", []string{"b"},
+		[]Scope{ScopeLogGroup}},
+	ActionInsights: {"insights", "add the log group to a Logs Insights query", []string{"i"},
+		[]Scope{ScopeLogGroup}},
+	ActionProfile: {"profile", "switch the AWS profile and region", []string{"p"},
+		[]Scope{ScopeLogGroup}},
+	ActionRename: {"rename", "rename the selected bookmark", []string{"r"},
+		[]Scope{ScopeBookmark}},
+	ActionDelete: {"delete", "delete the selected bookmark, press twice to confirm", []string{"d"},
+		[]Scope{ScopeBookmark}},
+	ActionTestFilter: {"test-filter", "test the filter pattern on the loaded events", []string{"Ctrl-T"},
+		[]Scope{ScopeFilterInput}},
+	ActionToggleRegex: {"toggle-regex", "switch the search between plain text and regular expressions", []string{"Ctrl-R"},
+		[]Scope{ScopeSearchInput}},
+	ActionToggleSelect: {"toggle-select", "mark or unmark the log group or stream, Enter then opens the events of all marked ones", []string{"Space"},
+		[]Scope{ScopeLogGroup, ScopeLogStream}},
+	ActionSelectAll: {"select-all", "mark all log streams of the page", []string{"a"},
+		[]Scope{ScopeLogStream}},
+	ActionClearSelection: {"clear-selection", "unmark all log streams", []string{"c"},
+		[]Scope{ScopeLogStream}},
+	ActionInvertSelection: {"invert-selection", "invert the marks of the log streams of the page", []string{"v"},
+		[]Scope{ScopeLogStream}},
+	ActionConsoleLink: {"console-link", "copy the CloudWatch console link of the selected event or of the view", []string{"L"},
+		[]Scope{ScopeLogView}},
+	ActionVisual: {"visual", "start or end a visual selection of events in the event table", []string{"v"},
+		[]Scope{ScopeLogView}},
+	ActionYank: {"yank", "copy the messages of the selected events to the clipboard", []string{"y"},
+		[]Scope{ScopeLogView}},
+	ActionYankJSON: {"yank-jsonl", "copy the selected events to the clipboard as JSON Lines", []string{"Y"},
+		[]Scope{ScopeLogView}},
+}
+
+// ActionNames returns the names of all actions in documentation order.
+func ActionNames() []string {
+	names := make([]string, 0, numActions)
+	for _, info := range Actions[ActionNone+1:] {
+		names = append(names, info.Name)
+	}
+	return names
+}
+
+// Key is a key press that can be bound to an action.
+type Key struct {
+	key tcell.Key
+	ch  rune
+	mod tcell.ModMask
+}
+
+// eventKey returns the key of a key event. Modifiers that are already part
+// of the key, like Shift for an upper case letter, are dropped.
+func eventKey(event *tcell.EventKey) Key {
+	mod := event.Modifiers()
+	switch {
+	case event.Key() == tcell.KeyRune:
+		return Key{key: tcell.KeyRune, ch: event.Rune(), mod: mod & tcell.ModAlt}
+	case event.Key() < tcell.KeyRune:
+		// control characters like Ctrl-A, Tab and Enter
+		return Key{key: event.Key(), mod: mod & tcell.ModAlt}
+	default:
+		return Key{key: event.Key(), mod: mod & (tcell.ModCtrl | tcell.ModAlt | tcell.ModShift)}
+	}
+}
+
+// typing reports whether the key types a character into a text input.
+func (k Key) typing() bool {
+	return k.key == tcell.KeyRune && k.mod == tcell.ModNone
+}
+
+// String returns the name of the key in the form it is configured, e.g. "j", "Ctrl-N" or "Alt-Down".
+func (k Key) String() string {
+	var name string
+	switch {
+	case k.key == tcell.KeyRune && k.ch == ' ':
+		name = "Space"
+	case k.key == tcell.KeyRune:
+		name = string(k.ch)
+	default:
+		name = tcell.KeyNames[k.key]
+	}
+	if k.mod&tcell.ModShift != 0 {
+		name = "Shift-" + name
+	}
+	if k.mod&tcell.ModAlt != 0 {
+		name = "Alt-" + name
+	}
+	if k.mod&tcell.ModCtrl != 0 {
+		name = "Ctrl-" + name
+	}
+	return name
+}
+
+// keyAliases are additional names of keys accepted in the config file.
+var keyAliases = map[string]tcell.Key{
+	"escape":   tcell.KeyEsc,
+	"return":   tcell.KeyEnter,
+	"pageup":   tcell.KeyPgUp,
+	"pagedown": tcell.KeyPgDn,
+	"del":      tcell.KeyDelete,
+	"ins":      tcell.KeyInsert,
+}
+
+// ParseKey parses a key like "j", "/", "Space", "Tab", "Enter", "Esc", "Down",
+// "F5", "Ctrl-N", "Alt-x" or "Shift-Tab". The emacs forms "C-n" and "M-x" are
+// accepted as well.
+func ParseKey(text string) (Key, error) {
+	var mod tcell.ModMask
+	rest := text
+	for utf8.RuneCountInString(rest) > 2 {
+		prefix, after, ok := strings.Cut(rest, "-")
+		if !ok || after == "" {
+			break
+		}
+		switch strings.ToLower(prefix) {
+		case "ctrl", "c":
+			mod |= tcell.ModCtrl
+		case "alt", "meta", "m":
+			mod |= tcell.ModAlt
+		case "shift", "s":
+			mod |= tcell.ModShift
+		default:
+			return Key{}, fmt.Errorf("unknown modifier %q in key %q", prefix, text)
+		}
+		rest = after
+	}
+	if rest == "" {
+		return Key{}, errors.New("empty key")
+	}
+
+	if strings.EqualFold(rest, "space") {
+		rest = " "
+	}
+	if r, size := utf8.DecodeRuneInString(rest); size == len(rest) && r != utf8.RuneError {
+		switch {
+		case mod&tcell.ModCtrl != 0:
+			c := unicode.ToLower(r)
+			switch {
+			case c == ' ':
+				return Key{key: tcell.KeyCtrlSpace, mod: mod & tcell.ModAlt}, nil
+			case c >= 'a' && c <= 'z' && mod&tcell.ModShift == 0:
+				return Key{key: tcell.KeyCtrlA + tcell.Key(c-'a'), mod: mod & tcell.ModAlt}, nil
+			}
+			return Key{}, fmt.Errorf("unsupported key %q, Ctrl can only be combined with a letter or a named key", text)
+		case mod&tcell.ModShift != 0:
+			r = unicode.ToUpper(r)
+		}
+		return Key{key: tcell.KeyRune, ch: r, mod: mod & tcell.ModAlt}, nil
+	}
+
+	key, ok := keyAliases[strings.ToLower(rest)]
+	if !ok {
+		for k, name := range tcell.KeyNames {
+			// Ctrl-A and the like are parsed as a modifier and a letter above
+			if strings.EqualFold(name, rest) && !strings.HasPrefix(name, "Ctrl-") {
+				key, ok = k, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return Key{}, fmt.Errorf("unknown key %q", text)
+	}
+	if key == tcell.KeyTab && mod == tcell.ModShift {
+		return Key{key: tcell.KeyBacktab}, nil
+	}
+	if key < tcell.KeyRune {
+		// Tab, Enter, Esc and Backspace are control characters, terminals only report Alt with them
+		if mod&^tcell.ModAlt != 0 {
+			return Key{}, fmt.Errorf("unsupported key %q, %s can only be combined with Alt", text, tcell.KeyNames[key])
+		}
+	}
+	return Key{key: key, mod: mod}, nil
+}
+
+// Keymap maps the keys of each scope to actions.
+type Keymap struct {
+	bindings [numScopes]map[Key]Action
+	keys     [numActions][]Key
+}
+
+// DefaultKeymap returns the keymap with the default keys of all actions.
+func DefaultKeymap() *Keymap {
+	k, err := NewKeymap(nil)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default keys: %v", err))
+	}
+	return k
+}
+
+// NewKeymap creates a keymap from the default keys, replacing the keys of
+// the actions named in overrides. An empty list of keys unbinds an action.
+// Unknown actions, invalid keys and keys bound to several actions in the same
+// scope are all reported.
+func NewKeymap(overrides map[string][]string) (*Keymap, error) {
+	var errs []error
+	names := make(map[string]Action, numActions)
+	for a, info := range Actions {
+		names[info.Name] = Action(a)
+	}
+	for name := range overrides {
+		if _, ok := names[name]; !ok {
+			errs = append(errs, fmt.Errorf("keys: unknown action %q, available actions are %s", name, strings.Join(ActionNames(), ", ")))
+		}
+	}
+
+	k := &Keymap{}
+	for s := range k.bindings {
+		k.bindings[s] = make(map[Key]Action)
+	}
+	for a := ActionNone + 1; a < numActions; a++ {
+		info := Actions[a]
+		texts, ok := overrides[info.Name]
+		if !ok {
+			texts = info.Keys
+		}
+		for _, text := range texts {
+			key, err := ParseKey(text)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("keys.%s: %w", info.Name, err))
+				continue
+			}
+			if slices.Contains(k.keys[a], key) {
+				continue
+			}
+			k.keys[a] = append(k.keys[a], key)
+			for _, s := range info.Scopes {
+				if s.typed() && key.typing() {
+					continue
+				}
+				if other, ok := k.bindings[s][key]; ok {
+					errs = append(errs, fmt.Errorf("keys: %s is bound to both %s and %s in the %s",
+						key, Actions[other].Name, info.Name, ScopeNames[s]))
+					continue
+				}
+				k.bindings[s][key] = a
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return k, nil
+}
+
+// Action returns the action the key event is bound to in the scope,
+// or ActionNone if it is not bound.
+func (k *Keymap) Action(scope Scope, event *tcell.EventKey) Action {
+	return k.bindings[scope][eventKey(event)]
+}
+
+// Keys returns the names of the keys bound to the action.
+func (k *Keymap) Keys(action Action) []string {
+	names := make([]string, len(k.keys[action]))
+	for i, key := range k.keys[action] {
+		names[i] = key.String()
+	}
+	return names
+}
+
+// Hint returns the keys bound to the action for a hint in the UI, e.g. "m" or "n/F3",
+// so that hints follow the configured keys. An unbound action is hinted by its name.
+func (k *Keymap) Hint(action Action) string {
+	keys := k.Keys(action)
+	if len(keys) == 0 {
+		return Actions[action].Name + " (unbound)"
+	}
+	return strings.Join(keys, "/")
+}
+
+// Binding is an action and the keys it is bound to in a scope.
+type Binding struct {
+	Action Action
+	Keys   []string
+}
+
+// Bindings returns the actions bound in the scope with their keys, in documentation order.
+// It is read from the same bindings as Action, so it lists exactly the keys that work.
+func (k *Keymap) Bindings(scope Scope) []Binding {
+	var bindings []Binding
+	for a := ActionNone + 1; a < numActions; a++ {
+		var keys []string
+		for _, key := range k.keys[a] {
+			if k.bindings[scope][key] == a {
+				keys = append(keys, key.String())
+			}
+		}
+		if len(keys) > 0 {
+			bindings = append(bindings, Binding{Action: a, Keys: keys})
+		}
+	}
+	return bindings
+}
@@ -29,12 +29,67 @@ var LayoutNames = map[Layout]string{
 type Layouts struct {
 	LogGroupAndStream *tview.Flex
 	LogEvent          *tview.Grid
+	LogEventViews     *tview.Pages
+	Insights          *tview.Grid
+	Error             *tview.Modal
+	Profile           *tview.Flex
+	Bookmark          *tview.Flex
+	Help              *tview.Flex
+	Root              *tview.Flex
 }
 
 // setUp initializes all layouts with their respective widget configurations.
 func (l *Layouts) setUp(w *Widgets) {
 	l.setUpLayoutLogGroupAndStream(w)
 	l.setUpLayoutLogEvent(w)
+	l.setUpLayoutInsights(w)
+	l.setUpLayoutError(w)
+	l.setUpLayoutProfile(w)
+	l.setUpLayoutBookmark(w)
+	l.setUpLayoutHelp(w)
+}
+
+// setUpLayoutProfile creates the layout for the profile and region picker.
+// It places the profile list and the region list side by side.
+func (l *Layouts) setUpLayoutProfile(w *Widgets) {
+	l.Profile = tview.NewFlex().
+		AddItem(w.Profile.Profiles, 0, 1, true).
+		AddItem(w.Profile.Regions, 0, 1, false)
+}
+
+// setUpLayoutBookmark creates the layout for the bookmarks page.
+// It places the bookmark table above the name input and the status line.
+func (l *Layouts) setUpLayoutBookmark(w *Widgets) {
+	l.Bookmark = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(w.Bookmark.Table, 0, 1, true).
+		AddItem(w.Bookmark.Name, 1, 0, false).
+		AddItem(w.Bookmark.Status, 1, 0, false)
+}
+
+// setUpLayoutHelp centers the help text on top of the page that is currently shown.
+// The empty items around it leave the page below visible.
+func (l *Layouts) setUpLayoutHelp(w *Widgets) {
+	l.Help = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(w.Help.Text, 0, 4, true).
+			AddItem(nil, 0, 1, false), 0, 4, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// setUpRoot creates the root layout with the header above all pages.
+// It is set up after the pages since it contains them.
+func (l *Layouts) setUpRoot(w *Widgets, p *Pages) {
+	l.Root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(w.Header.Text, 1, 0, false).
+		AddItem(p.Pages, 0, 1, true)
+}
+
+// setUpLayoutError uses the error modal as its own layout.
+// The modal centers itself on top of the page that is currently shown.
+func (l *Layouts) setUpLayoutError(w *Widgets) {
+	l.Error = w.Error.Modal
 }
 
 // setUpLayoutLogGroupAndStream creates the layout for log group and stream selection.
@@ -51,90 +106,191 @@ func (l *Layouts) setUpLayoutLogGroupAndStream(w *Widgets) {
 // setUpLayoutLogEvent creates the grid layout for the log event viewer.
 // It arranges date/time selectors, filter options, and the log display area.
 func (l *Layouts) setUpLayoutLogEvent(w *Widgets) {
+	// the log events are shown either as text or as a table with a detail pane
+	l.LogEventViews = tview.NewPages().
+		AddPage(WidgetNames[ViewLog], w.LogEvent.ViewLog, true, true).
+		AddPage(WidgetNames[EventTable], tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(w.LogEvent.EventTable, 0, 2, true).
+			AddItem(w.LogEvent.EventDetail, 0, 1, false),
+			true, false)
+	eventBody := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(l.LogEventViews, 0, 1, true).
+		AddItem(tview.NewFlex().
+			AddItem(w.LogEvent.Search, 0, 1, false).
+			AddItem(w.LogEvent.SearchStatus, 36, 0, false),
+			1, 0, false)
+
 	l.LogEvent = tview.NewGrid().
 		SetRows(
+			// time range presets
+			1,
 			// drop down options
 			1, 1, 1,
+			// filter pattern status
+			1,
 			// text view
 			0).
 		SetColumns(0, 0, 0, 0, 0).
 		SetBorders(true).
-		// start date
-		AddItem(w.LogEvent.StartYear,
+		// time range
+		AddItem(w.LogEvent.Preset,
 			0, 0, // row, column position
 			1, 1, // rowSpan, columnSpan
 			0, 100, // minHeight, minWidth
 			false). // focusable
-		AddItem(w.LogEvent.StartMonth,
+		AddItem(w.LogEvent.TimeRange,
 			0, 1,
+			1, 3,
+			0, 100,
+			false).
+		AddItem(w.LogEvent.EventCount,
+			0, 4,
+			1, 1,
+			0, 100,
+			false).
+		// start date
+		AddItem(w.LogEvent.StartYear,
+			1, 0,
+			1, 1,
+			0, 100,
+			false).
+		AddItem(w.LogEvent.StartMonth,
+			1, 1,
 			1, 1,
 			0, 100,
 			false).
 		AddItem(w.LogEvent.StartDay,
-			0, 2,
+			1, 2,
 			1, 1,
 			0, 100,
 			false).
 		AddItem(w.LogEvent.StartHour,
-			0, 3,
+			1, 3,
 			1, 1,
 			0, 100,
 			false).
 		AddItem(w.LogEvent.StartMinute,
-			0, 4,
+			1, 4,
 			1, 1,
 			0, 100,
 			false).
 		// end date
 		AddItem(w.LogEvent.EndYear,
-			1, 0,
+			2, 0,
 			1, 1,
 			0, 100,
 			false).
 		AddItem(w.LogEvent.EndMonth,
-			1, 1,
+			2, 1,
 			1, 1,
 			0, 100,
 			false).
 		AddItem(w.LogEvent.EndDay,
-			1, 2,
+			2, 2,
 			1, 1,
 			0, 100,
 			false).
 		AddItem(w.LogEvent.EndHour,
-			1, 3,
+			2, 3,
 			1, 1,
 			0, 100,
 			false).
 		AddItem(w.LogEvent.EndMinute,
-			1, 4,
+			2, 4,
 			1, 1,
 			0, 100,
 			false).
 		// additional input
 		AddItem(w.LogEvent.FilterPatern,
-			2, 0,
+			3, 0,
 			1, 1,
 			0, 100,
 			false).
 		AddItem(w.LogEvent.OutputFile,
-			2, 1,
+			3, 1,
 			1, 1,
 			0, 100,
 			false).
-		AddItem(w.LogEvent.SaveEventLog,
-			2, 2,
+		AddItem(tview.NewFlex().
+			AddItem(w.LogEvent.OutputFormat, 14, 0, false).
+			AddItem(w.LogEvent.SaveEventLog, 0, 1, false),
+			3, 2,
 			1, 1,
 			0, 100,
 			false).
 		AddItem(w.LogEvent.Back,
-			2, 3,
+			3, 3,
+			1, 1,
+			0, 100,
+			false).
+		AddItem(w.LogEvent.Status,
+			3, 4,
 			1, 1,
 			0, 100,
 			false).
+		AddItem(w.LogEvent.FilterStatus,
+			4, 0,
+			1, 5,
+			0, 100,
+			false).
 		// Log View
-		AddItem(w.LogEvent.ViewLog,
-			3, 0,
+		AddItem(eventBody,
+			5, 0,
+			1, 5,
+			0, 100,
+			false)
+}
+
+// setUpLayoutInsights creates the grid layout for the Logs Insights query page.
+// It arranges the query editor, selected log groups, actions and the result table.
+func (l *Layouts) setUpLayoutInsights(w *Widgets) {
+	l.Insights = tview.NewGrid().
+		SetRows(
+			// query editor
+			5,
+			// actions
+			1,
+			// result table
+			0).
+		SetColumns(0, 0, 0, 0, 0).
+		SetBorders(true).
+		AddItem(w.Query.Query,
+			0, 0, // row, column position
+			1, 3, // rowSpan, columnSpan
+			0, 100, // minHeight, minWidth
+			false). // focusable
+		AddItem(w.Query.LogGroups,
+			0, 3,
+			1, 2,
+			0, 100,
+			false).
+		AddItem(w.Query.Range,
+			1, 0,
+			1, 1,
+			0, 100,
+			false).
+		AddItem(w.Query.Run,
+			1, 1,
+			1, 1,
+			0, 100,
+			false).
+		AddItem(w.Query.Stop,
+			1, 2,
+			1, 1,
+			0, 100,
+			false).
+		AddItem(w.Query.Back,
+			1, 3,
+			1, 1,
+			0, 100,
+			false).
+		AddItem(w.Query.Status,
+			1, 4,
+			1, 1,
+			0, 100,
+			false).
+		AddItem(w.Query.Results,
+			2, 0,
 			1, 5,
 			0, 100,
 			false)
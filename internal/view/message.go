@@ -0,0 +1,210 @@
+// Package view provides UI components and layouts for the CloudWatch Log TUI.
+package view
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// MessageMode specifies how log messages are rendered in the log viewer.
+type MessageMode int
+
+const (
+	// MessageRaw shows messages as they are
+	MessageRaw MessageMode = iota
+	// MessageCompact shows JSON messages on a single line
+	MessageCompact
+	// MessagePretty shows JSON messages indented
+	MessagePretty
+	// MessageColor shows JSON messages indented with colored keys and values
+	MessageColor
+)
+
+// MessageModeNames provides display names for each message mode.
+var MessageModeNames = map[MessageMode]string{
+	MessageRaw:     "raw",
+	MessageCompact: "compact",
+	MessagePretty:  "pretty",
+	MessageColor:   "color",
+}
+
+// Next returns the mode following m, wrapping around to MessageRaw.
+func (m MessageMode) Next() MessageMode {
+	return (m + 1) % MessageMode(len(MessageModeNames))
+}
+
+// Colors of JSON tokens in the MessageColor mode
+const (
+	jsonKeyColor    = "skyblue"
+	jsonStringColor = "lightgreen"
+	jsonNumberColor = "yellow"
+	jsonOtherColor  = "fuchsia"
+)
+
+// levelKeys are the JSON fields holding the level of a structured log message.
+var levelKeys = []string{"level", "severity", "lvl", "loglevel"}
+
+// FormatMessage renders a log message for a text view with dynamic colors.
+// JSON messages are reformatted according to mode and colored by their level field,
+// other messages are kept as they are. User content is always escaped with escape,
+// or tview.Escape if it is nil.
+func FormatMessage(message string, mode MessageMode, escape Escaper) string {
+	if escape == nil {
+		escape = tview.Escape
+	}
+	trimmed := strings.TrimSpace(message)
+	if mode == MessageRaw || !isJSONObject(trimmed) {
+		return escape(message)
+	}
+
+	color := levelColor(trimmed)
+	switch mode {
+	case MessageCompact:
+		var b bytes.Buffer
+		if err := json.Compact(&b, []byte(trimmed)); err != nil {
+			return escape(message)
+		}
+		return colorLines(escape(b.String()), color) + "\n"
+	case MessagePretty:
+		var b bytes.Buffer
+		if err := json.Indent(&b, []byte(trimmed), "", "  "); err != nil {
+			return escape(message)
+		}
+		return colorLines(escape(b.String()), color) + "\n"
+	default:
+		var b strings.Builder
+		dec := json.NewDecoder(strings.NewReader(trimmed))
+		dec.UseNumber()
+		if err := colorizeJSON(&b, dec, escape, "", "", color); err != nil {
+			return escape(message)
+		}
+		return b.String() + "\n"
+	}
+}
+
+// isJSONObject returns true if the text is a JSON object.
+func isJSONObject(text string) bool {
+	return strings.HasPrefix(text, "{") && json.Valid([]byte(text))
+}
+
+// levelColor returns the color of a JSON message according to its level field,
+// or an empty string if it has none or the level needs no highlighting.
+func levelColor(text string) string {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(text), &fields); err != nil {
+		return ""
+	}
+	for _, key := range levelKeys {
+		level, ok := fields[key].(string)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(level) {
+		case "fatal", "panic", "critical", "crit", "alert", "emerg", "emergency", "error", "err":
+			return "red"
+		case "warn", "warning":
+			return "yellow"
+		case "debug", "trace":
+			return "gray"
+		}
+		return ""
+	}
+	return ""
+}
+
+// colorLines wraps each line of text in the color, so that it survives line wrapping.
+func colorLines(text string, color string) string {
+	if color == "" {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = "[" + color + "]" + line + "[-]"
+	}
+	return strings.Join(lines, "\n")
+}
+
+// colorizeJSON writes the next JSON value of the decoder indented and colored.
+// key is the name of the field holding the value, used to highlight the level field.
+func colorizeJSON(b *strings.Builder, dec *json.Decoder, escape Escaper, indent string, key string, level string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		closing := "}"
+		if t == '[' {
+			closing = "]"
+		}
+		b.WriteString(tview.Escape(t.String()))
+
+		empty := true
+		for dec.More() {
+			if !empty {
+				b.WriteString(",")
+			}
+			empty = false
+			b.WriteString("\n" + indent + "  ")
+
+			field := ""
+			if t == '{' {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				field, _ = keyTok.(string)
+				b.WriteString(colored(escape(quoteJSON(field)), jsonKeyColor) + ": ")
+			}
+			if err := colorizeJSON(b, dec, escape, indent+"  ", field, level); err != nil {
+				return err
+			}
+		}
+		// consume the closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+		if !empty {
+			b.WriteString("\n" + indent)
+		}
+		b.WriteString(tview.Escape(closing))
+	case string:
+		color := jsonStringColor
+		if level != "" && slices.Contains(levelKeys, key) {
+			color = level
+		}
+		b.WriteString(colored(escape(quoteJSON(t)), color))
+	case json.Number:
+		b.WriteString(colored(escape(t.String()), jsonNumberColor))
+	case bool:
+		if t {
+			b.WriteString(colored(escape("true"), jsonOtherColor))
+		} else {
+			b.WriteString(colored(escape("false"), jsonOtherColor))
+		}
+	case nil:
+		b.WriteString(colored(escape("null"), jsonOtherColor))
+	}
+	return nil
+}
+
+// colored wraps escaped text in the color.
+func colored(text string, color string) string {
+	return "[" + color + "]" + text + "[-]"
+}
+
+// quoteJSON returns s as a JSON string literal without HTML escaping.
+func quoteJSON(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return s
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
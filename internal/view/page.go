@@ -13,6 +13,16 @@ const (
 	LogGroupAndStreamPage Page = iota
 	// LogEventPage displays the log events viewer interface
 	LogEventPage
+	// InsightsPage displays the Logs Insights query interface
+	InsightsPage
+	// ErrorPage displays an error dialog on top of the current page
+	ErrorPage
+	// ProfilePage displays the AWS profile and region picker
+	ProfilePage
+	// BookmarkPage lists the saved bookmarks of log event views
+	BookmarkPage
+	// HelpPage displays the keys of the focused panel on top of the current page
+	HelpPage
 )
 
 // PageNames provides string identifiers for each page type.
@@ -20,6 +30,11 @@ const (
 var PageNames = map[Page]string{
 	LogGroupAndStreamPage: "logGroups",
 	LogEventPage:          "logEvents",
+	InsightsPage:          "insights",
+	ErrorPage:             "error",
+	ProfilePage:           "profile",
+	BookmarkPage:          "bookmarks",
+	HelpPage:              "help",
 }
 
 // Pages manages the different screens in the application.
@@ -33,5 +48,10 @@ type Pages struct {
 func (p *Pages) setUp(l *Layouts) {
 	p.Pages = tview.NewPages().
 		AddPage(PageNames[LogGroupAndStreamPage], l.LogGroupAndStream, true, true).
-		AddPage(PageNames[LogEventPage], l.LogEvent, true, false)
+		AddPage(PageNames[LogEventPage], l.LogEvent, true, false).
+		AddPage(PageNames[InsightsPage], l.Insights, true, false).
+		AddPage(PageNames[ProfilePage], l.Profile, true, false).
+		AddPage(PageNames[BookmarkPage], l.Bookmark, true, false).
+		AddPage(PageNames[HelpPage], l.Help, true, false).
+		AddPage(PageNames[ErrorPage], l.Error, true, false)
 }
@@ -0,0 +1,78 @@
+// Package view provides UI components and layouts for the CloudWatch Log TUI.
+package view
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// Escaper escapes user content for a text view with dynamic colors.
+// It may add color and region tags around parts of the content.
+type Escaper func(text string) string
+
+// searchMatchColor is the color of search matches in the log viewer.
+// The current match is additionally highlighted as a region.
+const searchMatchColor = "black:yellow"
+
+// CompileSearch compiles a search query of the log viewer.
+// A plain query matches literally and ignores case, a regex query is used as it is.
+func CompileSearch(query string, regex bool) (*regexp.Regexp, error) {
+	if regex {
+		re, err := regexp.Compile(query)
+		if err != nil {
+			// the syntax error code is short enough to be shown inline
+			var syntaxErr *syntax.Error
+			if errors.As(err, &syntaxErr) {
+				return nil, fmt.Errorf("%s", syntaxErr.Code)
+			}
+			return nil, err
+		}
+		return re, nil
+	}
+	return regexp.MustCompile("(?i)" + regexp.QuoteMeta(query)), nil
+}
+
+// MatchRegion returns the region ID of the i-th search match.
+func MatchRegion(i int) string {
+	return fmt.Sprintf("match%d", i)
+}
+
+// SearchHighlighter escapes text and marks the matches of a search as regions
+// with sequential IDs, so that they can be navigated with tview highlights.
+type SearchHighlighter struct {
+	re    *regexp.Regexp
+	count int
+}
+
+// NewSearchHighlighter creates a SearchHighlighter for the compiled search.
+func NewSearchHighlighter(re *regexp.Regexp) *SearchHighlighter {
+	return &SearchHighlighter{re: re}
+}
+
+// Escape escapes text, wrapping each match in a colored region.
+func (h *SearchHighlighter) Escape(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range h.re.FindAllStringIndex(text, -1) {
+		// empty matches cannot be highlighted
+		if loc[0] == loc[1] {
+			continue
+		}
+		b.WriteString(tview.Escape(text[last:loc[0]]))
+		fmt.Fprintf(&b, `["%s"][%s]%s[-:-][""]`, MatchRegion(h.count), searchMatchColor, tview.Escape(text[loc[0]:loc[1]]))
+		h.count++
+		last = loc[1]
+	}
+	b.WriteString(tview.Escape(text[last:]))
+	return b.String()
+}
+
+// Count returns the number of matches marked so far.
+func (h *SearchHighlighter) Count() int {
+	return h.count
+}
@@ -0,0 +1,66 @@
+// Package view provides UI components and layouts for the CloudWatch Log TUI.
+package view
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// DefaultTheme is the theme used when none is configured.
+const DefaultTheme = "default"
+
+// Themes provides the color themes that can be configured by name.
+var Themes = map[string]tview.Theme{
+	DefaultTheme: tview.Styles,
+	"light": {
+		PrimitiveBackgroundColor:    tcell.ColorWhite,
+		ContrastBackgroundColor:     tcell.ColorLightGray,
+		MoreContrastBackgroundColor: tcell.ColorSilver,
+		BorderColor:                 tcell.ColorBlack,
+		TitleColor:                  tcell.ColorBlack,
+		GraphicsColor:               tcell.ColorBlack,
+		PrimaryTextColor:            tcell.ColorBlack,
+		SecondaryTextColor:          tcell.ColorNavy,
+		TertiaryTextColor:           tcell.ColorDarkGreen,
+		InverseTextColor:            tcell.ColorWhite,
+		ContrastSecondaryTextColor:  tcell.ColorDarkRed,
+	},
+	"monochrome": {
+		PrimitiveBackgroundColor:    tcell.ColorDefault,
+		ContrastBackgroundColor:     tcell.ColorDefault,
+		MoreContrastBackgroundColor: tcell.ColorDefault,
+		BorderColor:                 tcell.ColorDefault,
+		TitleColor:                  tcell.ColorDefault,
+		GraphicsColor:               tcell.ColorDefault,
+		PrimaryTextColor:            tcell.ColorDefault,
+		SecondaryTextColor:          tcell.ColorDefault,
+		TertiaryTextColor:           tcell.ColorDefault,
+		InverseTextColor:            tcell.ColorDefault,
+		ContrastSecondaryTextColor:  tcell.ColorDefault,
+	},
+}
+
+// ThemeNames returns the names of the available themes in sorted order.
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ApplyTheme sets the colors of the named theme.
+// It must be called before the view is created, since widgets copy the colors when they are set up.
+func ApplyTheme(name string) error {
+	theme, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q, available themes are %v", name, ThemeNames())
+	}
+	tview.Styles = theme
+	FilterValidColor = theme.PrimaryTextColor
+	return nil
+}
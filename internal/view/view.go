@@ -21,5 +21,6 @@ func New() *View {
 	v.Widgets.setUp()
 	v.Layouts.setUp(v.Widgets)
 	v.Pages.setUp(v.Layouts)
+	v.Layouts.setUpRoot(v.Widgets, v.Pages)
 	return v
 }
@@ -3,6 +3,7 @@ package view
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -21,6 +22,8 @@ const (
 	LogStreamTable
 
 	// Log event form widgets
+	TimePresetDropDown
+	TimeRangeInput
 	StartYearDropDown
 	StartMonthDropDown
 	StartDayDropDown
@@ -33,39 +36,130 @@ const (
 	EndMinuteDropDown
 	FilterPatternInput
 	OutputFileInput
+	OutputFormatDropDown
 	SaveEventLogButton
 	BackButton
 	ViewLog
+	StatusView
+	EventCountView
+	EventTable
+	EventDetailView
+	EventSearchInput
+	SearchStatusView
+	FilterStatusView
+
+	// Insights query widgets
+	QueryInput
+	QueryRangeDropDown
+	QueryLogGroupsView
+	RunQueryButton
+	StopQueryButton
+	QueryBackButton
+	QueryStatusView
+	QueryResultTable
+
+	// Error widgets
+	ErrorModal
+
+	// Header and profile picker widgets
+	HeaderView
+	ProfileList
+	RegionList
+
+	// Bookmark widgets
+	BookmarkTable
+	BookmarkNameInput
+	BookmarkStatusView
+
+	// Help widgets
+	HelpView
 )
 
 // WidgetNames provides string identifiers for each widget type.
 // These are used for labeling and widget identification.
 var WidgetNames = map[Widget]string{
-	LogGroupTable:       "LogGroupTable",
-	LogGroupSearch:      "LogGroupSearch",
-	LogStreamTable:      "LogStreamTable",
-	StartYearDropDown:   "StartYear",
-	StartMonthDropDown:  "StartMonth",
-	StartDayDropDown:    "StartDay",
-	StartHourDropDown:   "StartHour",
-	StartMinuteDropDown: "StartMinute",
-	EndYearDropDown:     "EndYear",
-	EndMonthDropDown:    "EndMonth",
-	EndDayDropDown:      "EndDay",
-	EndHourDropDown:     "EndHour",
-	EndMinuteDropDown:   "EndMinute",
-	FilterPatternInput:  "FilterPattern",
-	OutputFileInput:     "OutputFile",
-	SaveEventLogButton:  "SaveEventLog",
-	BackButton:          "Back",
-	ViewLog:             "ViewLog",
+	LogGroupTable:        "LogGroupTable",
+	LogGroupSearch:       "LogGroupSearch",
+	LogStreamTable:       "LogStreamTable",
+	TimePresetDropDown:   "Preset",
+	TimeRangeInput:       "TimeRange",
+	StartYearDropDown:    "StartYear",
+	StartMonthDropDown:   "StartMonth",
+	StartDayDropDown:     "StartDay",
+	StartHourDropDown:    "StartHour",
+	StartMinuteDropDown:  "StartMinute",
+	EndYearDropDown:      "EndYear",
+	EndMonthDropDown:     "EndMonth",
+	EndDayDropDown:       "EndDay",
+	EndHourDropDown:      "EndHour",
+	EndMinuteDropDown:    "EndMinute",
+	FilterPatternInput:   "FilterPattern",
+	OutputFileInput:      "OutputFile",
+	OutputFormatDropDown: "Format",
+	SaveEventLogButton:   "SaveEventLog",
+	BackButton:           "Back",
+	ViewLog:              "ViewLog",
+	StatusView:           "Status",
+	EventCountView:       "EventCount",
+	EventTable:           "EventTable",
+	EventDetailView:      "EventDetail",
+	EventSearchInput:     "Search",
+	SearchStatusView:     "SearchStatus",
+	FilterStatusView:     "FilterStatus",
+	QueryInput:           "Query",
+	QueryRangeDropDown:   "Range",
+	QueryLogGroupsView:   "LogGroups",
+	RunQueryButton:       "Run",
+	StopQueryButton:      "Stop",
+	QueryBackButton:      "Back",
+	QueryStatusView:      "QueryStatus",
+	QueryResultTable:     "QueryResults",
+	ErrorModal:           "Error",
+	HeaderView:           "Header",
+	ProfileList:          "Profiles",
+	RegionList:           "Regions",
+	BookmarkTable:        "Bookmarks",
+	BookmarkNameInput:    "Name",
+	BookmarkStatusView:   "BookmarkStatus",
+	HelpView:             "Help",
 }
 
+// CustomTimePreset is the time preset used when the range is set with the dropdowns or an expression.
+const CustomTimePreset = "custom"
+
+// TimePresets lists the relative time ranges offered for log events.
+var TimePresets = []string{"last 5m", "last 15m", "last 1h", "last 3h", "last 12h", "last 24h", "last 7d", CustomTimePreset}
+
+// Labels of the search input for plain and regular expression searches
+const (
+	SearchLabel      = "Search: "
+	RegexSearchLabel = "Regex: "
+)
+
+// Text colors of the filter pattern input for valid and invalid patterns
+var (
+	FilterValidColor   = tview.Styles.PrimaryTextColor
+	FilterInvalidColor = tcell.ColorRed
+)
+
+// OutputFormats lists the formats log events can be saved in.
+// "auto" infers the format from the output file extension.
+var OutputFormats = []string{"auto", "raw", "jsonl", "csv", "text"}
+
+// QueryRanges lists the time ranges offered for Logs Insights queries.
+var QueryRanges = []string{"5m", "15m", "30m", "1h", "3h", "12h", "24h", "7d"}
+
 // Widgets contains all UI widget components organized by feature area.
 type Widgets struct {
 	LogGroup  logGroupWidget
 	LogStream logStreamWidget
 	LogEvent  logEventWidget
+	Query     queryWidget
+	Error     errorWidget
+	Header    headerWidget
+	Profile   profileWidget
+	Bookmark  bookmarkWidget
+	Help      helpWidget
 }
 
 type logGroupWidget struct {
@@ -76,6 +170,8 @@ type logStreamWidget struct {
 	Table *tview.Table
 }
 type logEventWidget struct {
+	Preset       *tview.DropDown
+	TimeRange    *tview.InputField
 	StartYear    *tview.DropDown
 	StartMonth   *tview.DropDown
 	StartDay     *tview.DropDown
@@ -88,9 +184,45 @@ type logEventWidget struct {
 	EndMinute    *tview.DropDown
 	FilterPatern *tview.InputField
 	OutputFile   *tview.InputField
+	OutputFormat *tview.DropDown
 	SaveEventLog *tview.Button
 	Back         *tview.Button
 	ViewLog      *tview.TextView
+	Status       *tview.TextView
+	EventCount   *tview.TextView
+	EventTable   *tview.Table
+	EventDetail  *tview.TextView
+	Search       *tview.InputField
+	SearchStatus *tview.TextView
+	FilterStatus *tview.TextView
+}
+type queryWidget struct {
+	Query     *tview.TextArea
+	Range     *tview.DropDown
+	LogGroups *tview.TextView
+	Run       *tview.Button
+	Stop      *tview.Button
+	Back      *tview.Button
+	Status    *tview.TextView
+	Results   *tview.Table
+}
+type errorWidget struct {
+	Modal *tview.Modal
+}
+type headerWidget struct {
+	Text *tview.TextView
+}
+type profileWidget struct {
+	Profiles *tview.List
+	Regions  *tview.List
+}
+type bookmarkWidget struct {
+	Table  *tview.Table
+	Name   *tview.InputField
+	Status *tview.TextView
+}
+type helpWidget struct {
+	Text *tview.TextView
 }
 
 // setUp initializes all widget groups with their default configurations.
@@ -98,6 +230,12 @@ func (w *Widgets) setUp() {
 	w.LogGroup.setUp()
 	w.LogStream.setUp()
 	w.LogEvent.setUp()
+	w.Query.setUp()
+	w.Error.setUp()
+	w.Header.setUp()
+	w.Profile.setUp()
+	w.Bookmark.setUp()
+	w.Help.setUp()
 }
 
 // setUp initializes the log group widget with a table and search field.
@@ -156,12 +294,11 @@ func dropDownOptions() map[Widget][]string {
 	for i := 0; i <= 59; i++ {
 		minutes = append(minutes, fmt.Sprintf("%d", i))
 	}
+	// the current year and the nine years before it
 	currentYear := time.Now().Year()
 	listOfYears := []string{}
-	num := -9
-	for i := 0; i < 10; i++ {
-		num++
-		listOfYears = append(listOfYears, fmt.Sprintf("%d", currentYear+i))
+	for i := 9; i >= 0; i-- {
+		listOfYears = append(listOfYears, fmt.Sprintf("%d", currentYear-i))
 	}
 
 	return map[Widget][]string{
@@ -178,10 +315,24 @@ func dropDownOptions() map[Widget][]string {
 	}
 }
 
+// OptionIndex returns the index of value among the options of a date/time dropdown,
+// or -1 if the dropdown has no such option.
+func OptionIndex(widget Widget, value string) int {
+	return slices.Index(dropDownOptions()[widget], value)
+}
+
 // setUp initializes the log event widget with date/time dropdowns,
 // filter inputs, action buttons, and a text viewer for log display.
 func (l *logEventWidget) setUp() {
 	optons := dropDownOptions()
+	l.Preset = tview.NewDropDown().
+		SetLabel(WidgetNames[TimePresetDropDown]).
+		SetOptions(TimePresets, nil).
+		SetFieldBackgroundColor(tcell.ColorGray)
+	l.TimeRange = tview.NewInputField().
+		SetLabel("Time Range ").
+		SetPlaceholder("-30m, 2024-06-01T10:00, yesterday 09:00..10:00").
+		SetFieldBackgroundColor(tcell.ColorGray)
 	l.StartYear = tview.NewDropDown().
 		SetLabel(WidgetNames[StartYearDropDown]).
 		SetOptions(optons[StartYearDropDown], nil).
@@ -225,9 +376,113 @@ func (l *logEventWidget) setUp() {
 
 	l.FilterPatern = tview.NewInputField().SetLabel("Write Filter Pattern")
 	l.OutputFile = tview.NewInputField().SetLabel("Write Output File")
+	l.OutputFormat = tview.NewDropDown().
+		SetLabel(WidgetNames[OutputFormatDropDown]).
+		SetOptions(OutputFormats, nil).
+		SetCurrentOption(0).
+		SetFieldBackgroundColor(tcell.ColorGray)
 
 	l.SaveEventLog = tview.NewButton("Save Button")
 	l.Back = tview.NewButton("Back Button")
 
-	l.ViewLog = tview.NewTextView()
+	l.ViewLog = tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true)
+	l.Status = tview.NewTextView().SetDynamicColors(true)
+	l.EventCount = tview.NewTextView().SetDynamicColors(true)
+
+	l.EventTable = tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	l.EventDetail = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	l.EventDetail.SetBorder(true).SetTitle("Detail")
+
+	l.Search = tview.NewInputField().
+		SetLabel(SearchLabel).
+		SetPlaceholderTextColor(tcell.ColorGray)
+	l.SearchStatus = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignRight)
+	l.FilterStatus = tview.NewTextView().SetDynamicColors(true)
+}
+
+// setUp initializes the Logs Insights widget with a query editor, time range,
+// action buttons, a progress view and a result table.
+func (q *queryWidget) setUp() {
+	q.Query = tview.NewTextArea().
+		SetPlaceholder("fields @timestamp, @message | sort @timestamp desc | limit 100")
+
+	q.Range = tview.NewDropDown().
+		SetLabel(WidgetNames[QueryRangeDropDown]).
+		SetOptions(QueryRanges, nil).
+		SetCurrentOption(slices.Index(QueryRanges, "1h")).
+		SetFieldBackgroundColor(tcell.ColorGray)
+
+	q.LogGroups = tview.NewTextView()
+
+	q.Run = tview.NewButton("Run Button")
+	q.Stop = tview.NewButton("Stop Button")
+	q.Back = tview.NewButton("Back Button")
+
+	q.Status = tview.NewTextView().SetDynamicColors(true)
+
+	q.Results = tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+}
+
+// setUp initializes the error widget with a modal dialog.
+// Its text and buttons are set each time an error is shown.
+func (e *errorWidget) setUp() {
+	e.Modal = tview.NewModal()
+	e.Modal.SetTitle(WidgetNames[ErrorModal])
+	e.Modal.SetBackgroundColor(tcell.ColorDarkRed)
+}
+
+// setUp initializes the header showing the active AWS profile and region.
+func (h *headerWidget) setUp() {
+	h.Text = tview.NewTextView().SetDynamicColors(true)
+}
+
+// setUp initializes the profile picker with lists of profiles and regions.
+// The list items are filled in when the picker is opened.
+func (p *profileWidget) setUp() {
+	p.Profiles = tview.NewList().ShowSecondaryText(false)
+	p.Profiles.SetTitle(WidgetNames[ProfileList])
+	p.Profiles.SetTitleAlign(tview.AlignLeft)
+	p.Profiles.SetBorder(true)
+
+	p.Regions = tview.NewList().ShowSecondaryText(false)
+	p.Regions.SetTitle(WidgetNames[RegionList])
+	p.Regions.SetTitleAlign(tview.AlignLeft)
+	p.Regions.SetBorder(true)
+}
+
+// setUp initializes the bookmarks page with a table of bookmarks,
+// an input for naming them and a status line with the available keys.
+func (b *bookmarkWidget) setUp() {
+	b.Table = tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	b.Table.SetTitle(WidgetNames[BookmarkTable])
+	b.Table.SetTitleAlign(tview.AlignLeft)
+	b.Table.SetBorder(true)
+
+	b.Name = tview.NewInputField().
+		SetLabel(WidgetNames[BookmarkNameInput] + ": ").
+		SetPlaceholderTextColor(tcell.ColorGray)
+	b.Status = tview.NewTextView().SetDynamicColors(true)
+}
+
+// setUp initializes the help widget with a bordered, scrollable text view.
+func (h *helpWidget) setUp() {
+	h.Text = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false)
+	h.Text.SetTitle(WidgetNames[HelpView])
+	h.Text.SetTitleAlign(tview.AlignLeft)
+	h.Text.SetBorder(true)
 }
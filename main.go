@@ -4,6 +4,8 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
@@ -12,14 +14,76 @@ import (
 	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
 	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
 	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/state"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/view"
 )
 
 // main initializes the application configuration, sets up AWS client connections,
 // and launches the terminal user interface for browsing CloudWatch logs.
 // It handles graceful shutdown on interrupt signals.
 func main() {
-	// Initialize configuration
-	cfg := config.New()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "config":
+			os.Exit(runConfig(os.Args[2:]))
+		case "tail":
+			os.Exit(runTail(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		}
+	}
+
+	fixtures := flag.String("fixtures", "", "serve logs from a fixture file or directory instead of AWS")
+	bookmark := flag.String("bookmark", "", "open the log event view saved as the named bookmark")
+	configPath := flag.String("config", "", "configuration file (default: $XDG_CONFIG_HOME/cloudwatch-log-tui/"+config.ConfigFile+")")
+	var link deepLink
+	flag.Var(&link.groups, "group", "open the log events of the log group, repeat to merge several")
+	flag.Var(&link.streams, "stream", "open the log events of the log stream, repeat for several (default: all streams)")
+	flag.StringVar(&link.since, "since", "", "time range of the log events to open: 30m, 2024-06-01T10:00, yesterday 09:00..10:00, ...")
+	flag.StringVar(&link.filter, "filter", "", "filter pattern of the log events to open")
+	applyFlags := config.RegisterFlags(flag.CommandLine)
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: cloudwatch-log-tui [flags] [CLOUDWATCH_CONSOLE_URL]")
+		fmt.Fprintln(flag.CommandLine.Output(), "       cloudwatch-log-tui tail|export|config -h")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		link.url = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Initialize configuration: defaults, config file, environment, flags
+	cfg, err := loadConfig(*configPath, applyFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration: %v\n", err)
+		os.Exit(2)
+	}
+	if err := view.ApplyTheme(cfg.Theme); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Log events to open directly, in the region of a console URL
+	var linkView config.Bookmark
+	if link.isSet() {
+		if *bookmark != "" {
+			fmt.Fprintln(os.Stderr, "-bookmark cannot be combined with -group, -stream, -since, -filter or a console URL")
+			os.Exit(2)
+		}
+		var region string
+		linkView, region, err = link.view(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid log events to open: %v\n", err)
+			os.Exit(2)
+		}
+		if region != "" {
+			cfg.Region = region
+		}
+	}
 
 	// Setup logging
 	logFile, err := cfg.InitLogging()
@@ -41,14 +105,23 @@ func main() {
 		cancel()
 	}()
 
-	// Initialize AWS client
-	awsClient, err := aws.NewClient(ctx)
+	awsClient, err := newBackend(ctx, cfg, *fixtures)
 	if err != nil {
-		log.Fatalf("error initializing AWS client: %v", err)
+		log.Fatal(err)
 	}
 
 	// Create UI
-	app := app.New(ctx, awsClient)
+	app := app.New(ctx, awsClient, cfg)
+	if *bookmark != "" {
+		if err := app.OpenBookmark(*bookmark); err != nil {
+			log.Fatalf("error opening bookmark: %v", err)
+		}
+	}
+	if link.isSet() {
+		if err := app.OpenView(linkView); err != nil {
+			log.Fatalf("error opening log events: %v", err)
+		}
+	}
 
 	go app.LoadLogGroups(state.Home)
 
@@ -57,3 +130,23 @@ func main() {
 		log.Fatalf("error running application: %v", err)
 	}
 }
+
+// newBackend initializes the logs backend: fixtures for offline use, AWS otherwise.
+func newBackend(ctx context.Context, cfg *config.Config, fixtures string) (aws.LogsBackend, error) {
+	if fixtures != "" {
+		backend, err := aws.NewMemoryBackend(fixtures)
+		if err != nil {
+			return nil, fmt.Errorf("error loading fixtures: %w", err)
+		}
+		return backend, nil
+	}
+	client, err := aws.NewClient(ctx, aws.ClientOptions{
+		Profile:     cfg.Profile,
+		Region:      cfg.Region,
+		EndpointURL: cfg.EndpointURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing AWS client: %w", err)
+	}
+	return client, nil
+}
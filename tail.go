@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/aws"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/config"
+	"github.com/ryutaro-asada/cloudwatch-log-tui/internal/state"
+)
+
+// tailFormats are the output formats of the tail subcommand.
+var tailFormats = []aws.OutputFormat{aws.FormatRaw, aws.FormatText, aws.FormatJSONL}
+
+// tailInterval is the delay between two polls with -follow.
+const tailInterval = 2 * time.Second
+
+// streamList collects the values of a repeatable flag.
+type streamList []string
+
+func (s *streamList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *streamList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// runTail runs the tail subcommand and returns the exit code.
+//
+//	tail -group G [-stream S ...] [-filter P] [-since 15m] [-follow] [-format raw|text|jsonl]
+//
+// prints the log events of a log group to stdout without the terminal UI.
+func runTail(args []string) int {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	group := fs.String("group", "", "log group to read the events of (required)")
+	var streams streamList
+	fs.Var(&streams, "stream", "log stream to read, repeat for several (default: all streams)")
+	filter := fs.String("filter", "", "CloudWatch filter pattern the events must match")
+	since := fs.String("since", "15m", "time range: 15m, 2024-06-01T10:00, yesterday 09:00..10:00, ...")
+	follow := fs.Bool("follow", false, "keep printing new events until interrupted")
+	formatName := fs.String("format", string(aws.FormatRaw), "output format: raw, text or jsonl")
+	fixtures := fs.String("fixtures", "", "read logs from a fixture file or directory instead of AWS")
+	configPath := fs.String("config", "", "configuration file (default: $XDG_CONFIG_HOME/cloudwatch-log-tui/"+config.ConfigFile+")")
+	applyFlags := config.RegisterFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: cloudwatch-log-tui tail -group G [-stream S ...] [-filter P] [-since 15m] [-follow] [-format raw|text|jsonl]")
+		fmt.Fprintf(fs.Output(), "exit codes: %d no events, %d credentials or permissions, %d throttled, %d other errors, %d usage\n",
+			exitNoEvents, exitAuth, exitThrottled, exitError, exitUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if err := checkTailFlags(*group, streams, *filter, *since, *follow, *formatName); err != nil {
+		fmt.Fprintf(os.Stderr, "tail: %v\n", err)
+		return exitUsage
+	}
+	cfg, err := loadConfig(*configPath, applyFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration: %v\n", err)
+		return exitUsage
+	}
+
+	tr, err := state.ParseTimeRange(*since, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tail: invalid -since: %v\n", err)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	backend, err := newBackend(ctx, cfg, *fixtures)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tail: %v\n", err)
+		return exitError
+	}
+	w, err := aws.NewEventWriter(os.Stdout, aws.OutputFormat(*formatName), false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tail: %v\n", err)
+		return exitUsage
+	}
+
+	t := &tailer{
+		backend: backend,
+		w:       w,
+		input: aws.LogEventInput{
+			Ctx:            ctx,
+			LogGroupName:   *group,
+			LogStreamNames: streams,
+			FilterPattern:  *filter,
+			Limit:          cfg.MaxEvents,
+		},
+	}
+	err = t.run(tr.Start, tr.End, *follow)
+
+	switch {
+	case ctx.Err() != nil && *follow:
+		// following ends with an interrupt
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "tail: %v\n", err)
+		return exitCode(err)
+	}
+	if t.printed == 0 {
+		fmt.Fprintln(os.Stderr, "tail: no events")
+		return exitNoEvents
+	}
+	return exitOK
+}
+
+// checkTailFlags checks the flags of the tail subcommand that do not need the configuration.
+func checkTailFlags(group string, streams []string, filter, since string, follow bool, formatName string) error {
+	if group == "" {
+		return errors.New("-group is required")
+	}
+	if err := aws.CheckLogStreamNames(streams); err != nil {
+		return fmt.Errorf("too many -stream: %w", err)
+	}
+	if _, err := aws.ParseFilterPattern(filter); err != nil {
+		return fmt.Errorf("invalid -filter: %w", err)
+	}
+	if follow && strings.Contains(since, "..") {
+		return errors.New("-follow cannot be used with a time range that has an end")
+	}
+	for _, format := range tailFormats {
+		if string(format) == formatName {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown -format %q, use raw, text or jsonl", formatName)
+}
+
+// tailer prints the log events of a query, page by page, and then polls for new ones.
+type tailer struct {
+	backend aws.LogsBackend
+	w       aws.EventWriter
+	input   aws.LogEventInput
+	// follower drops the events of overlapping polls that were printed before
+	follower *aws.Follower
+	printed  int
+}
+
+// run prints the events from start to end and, if follow is true, polls for newer ones
+// until the context of the input is cancelled.
+func (t *tailer) run(start time.Time, end time.Time, follow bool) error {
+	t.follower = aws.NewFollower(start)
+	if err := t.printRange(start, end); err != nil || !follow {
+		return err
+	}
+
+	ticker := time.NewTicker(tailInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.input.Ctx.Done():
+			return t.input.Ctx.Err()
+		case <-ticker.C:
+		}
+		if err := t.printRange(t.follower.From(), time.Now()); err != nil {
+			return err
+		}
+	}
+}
+
+// printRange prints all pages of events from start to end that were not printed before.
+func (t *tailer) printRange(start time.Time, end time.Time) error {
+	input := t.input
+	input.StartTime = start
+	input.EndTime = end
+	for {
+		output, err := t.backend.GetLogEvents(&input)
+		if err != nil {
+			return err
+		}
+		if err := t.printPage(output); err != nil {
+			return err
+		}
+		// FilterLogEvents may return a page with fewer events, even none, while more remain
+		if output.NextToken == nil {
+			return nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// printPage prints the new events of a page and flushes them, so that they show up immediately.
+func (t *tailer) printPage(output *aws.LogEventOutput) error {
+	events := t.follower.Unseen(t.input.LogGroupName, output.LogEvents)
+	if err := t.w.Write(events); err != nil {
+		return err
+	}
+	if err := t.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write events: %w", err)
+	}
+	t.printed += len(events)
+	return nil
+}